@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+var assignmentService = service.AssignmentService{}
+
+// GetOutstandingAssignments 尚未完成的作业
+func GetOutstandingAssignments(c *gin.Context) {
+	var req GameStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数格式错误"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"assignments": assignmentService.Outstanding(req.GameState),
+		},
+	})
+}
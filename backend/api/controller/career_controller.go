@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+var careerService = service.CareerService{}
+
+func GetCareers(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"careers": careerService.Catalog(),
+		},
+	})
+}
+
+// GetCareerEligibility 每个职业的入职要求角色还差什么，以及当前的职业与长期目标
+func GetCareerEligibility(c *gin.Context) {
+	var req GameStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数格式错误"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"careers": careerService.Eligibility(req.GameState),
+			"career":  req.GameState.Career,
+			"goals":   req.GameState.Goals,
+		},
+	})
+}
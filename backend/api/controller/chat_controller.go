@@ -1,10 +1,10 @@
 package controller
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -13,6 +13,7 @@ import (
 
 	"github.com/MaiXiangCatt/HogwartsSimulator/backend/config"
 	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
@@ -20,31 +21,14 @@ type Message struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
 }
-type Profile struct {
-	Name        string `json:"name"`
-	Gender      string `json:"gender"`
-	House       string `json:"house"`
-	BloodStatus string `json:"blood_status"`
-	Wand        string `json:"wand"`
-	Patronus    string `json:"patronus"`
-}
-type GameState struct {
-	Status        model.CharacterStatus `json:"status"`
-	Profile       Profile               `json:"profile"`
-	Inventory     model.InventoryMap    `json:"inventory"`
-	Spells        model.SpellMap        `json:"spells"`
-	Relationships model.RelationshipMap `json:"relationships"`
-	WorldLog      []string              `json:"world_log"`
-}
-
 type FrontedRequest struct {
-	Messages      []Message `json:"messages"`
-	Summary       []string  `json:"summary"`
-	Persona       string    `json:"persona"`
-	GameState     GameState `json:"game_state"`
-	APIKey        string    `json:"api_key"`
-	Model         string    `json:"model"`
-	UseMultiAgent bool      `json:"use_multi_agent"`
+	Messages      []Message       `json:"messages"`
+	Summary       []string        `json:"summary"`
+	Persona       string          `json:"persona"`
+	GameState     model.GameState `json:"game_state"`
+	APIKey        string          `json:"api_key"`
+	Model         string          `json:"model"`
+	UseMultiAgent bool            `json:"use_multi_agent"`
 }
 
 type AgentRequest struct {
@@ -54,12 +38,14 @@ type AgentRequest struct {
 }
 
 type MultiAgentRequest struct {
-	Messages  []Message `json:"messages"`
-	APIKey    string    `json:"api_key"`
-	Model     string    `json:"model"`
-	GameState GameState `json:"game_state"`
+	Messages  []Message       `json:"messages"`
+	APIKey    string          `json:"api_key"`
+	Model     string          `json:"model"`
+	GameState model.GameState `json:"game_state"`
 }
 
+var turnService = service.TurnService{}
+
 func ChatHandler(c *gin.Context) {
 	var req FrontedRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -70,6 +56,14 @@ func ChatHandler(c *gin.Context) {
 		c.JSON(400, gin.H{"error": "API Key 不能为空"})
 		return
 	}
+	// 规则引擎先行判定，不合法的行动不会发给模型
+	turnCtx, err := turnService.Prepare(&req.GameState, lastUserInput(req.Messages))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	req.GameState.TurnContext = turnCtx
+
 	isPrologue := req.GameState.Status.GameMode == "prologue"
 	if !isPrologue {
 		isPrologue = req.GameState.Status.CurrentYear == 1991 && req.GameState.Status.CurrentMonth <= 9
@@ -96,14 +90,14 @@ func ChatHandler(c *gin.Context) {
 			Messages:  agentMessages,
 			APIKey:    req.APIKey,
 			Model:     req.Model,
-			GameState: req.GameState,
+			GameState: req.GameState.ForPrompt(),
 		}
 		jsonData, jsonErr = json.Marshal(pyReq)
 		targetURL = "http://localhost:8000/multiagent/chat"
 	} else {
 		// 原始单 Agent 模式
 		// 2. 准备发给 Python 的数据
-		gameStateBytes, err := json.MarshalIndent(req.GameState, "", "  ") // Indent 是为了好看，调试方便
+		gameStateBytes, err := json.MarshalIndent(req.GameState.ForPrompt(), "", "  ") // Indent 是为了好看，调试方便
 		if err != nil {
 			c.JSON(500, gin.H{"error": "无法序列化状态"})
 			return
@@ -151,6 +145,11 @@ func ChatHandler(c *gin.Context) {
 	}
 	// 只有在函数结束时才关闭 Body，但在流式传输中，我们需要一直读
 	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// AI 服务没有正常生成，本回合不结算，Prepare 对状态的改动随请求一起丢弃
+		c.JSON(502, gin.H{"error": fmt.Sprintf("AI 服务返回错误 (%d)", resp.StatusCode)})
+		return
+	}
 
 	// 5. 设置 SSE (Server-Sent Events) 响应头
 	// 这告诉浏览器：我发的是流，不要缓存，保持连接
@@ -159,28 +158,69 @@ func ChatHandler(c *gin.Context) {
 	c.Writer.Header().Set("Connection", "keep-alive")
 	c.Writer.Header().Set("Transfer-Encoding", "chunked")
 
-	// 6. 直接从resp.Body读，往c.Writer写，因为python已经保证了格式正确
-	buf := make([]byte, 4096)
-	for {
-		select {
-		case <-c.Request.Context().Done():
-			// 前端关闭了连接
-			fmt.Println("前端链接已断开")
-			return
-		default:
-			n, err := resp.Body.Read(buf)
-			if n > 0 {
-				c.Writer.Write(buf[:n])
-				c.Writer.Flush()
+	// 6. 逐行转发 SSE，同时聚合正文用于回合结算
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	var fullReply strings.Builder
+	streamFailed := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		c.Writer.WriteString(line + "\n")
+		c.Writer.Flush()
+		if dataStr, ok := strings.CutPrefix(line, "data: "); ok {
+			var streamResp AIStreamResponse
+			if err := json.Unmarshal([]byte(dataStr), &streamResp); err != nil {
+				continue
 			}
-			if err != nil {
-				if err != io.EOF {
-					fmt.Println("Error reading response body:", err)
-				}
-				return
+			switch streamResp.Type {
+			case "text":
+				fullReply.WriteString(streamResp.Content)
+			case "error":
+				streamFailed = true
 			}
 		}
 	}
+	if c.Request.Context().Err() != nil {
+		// 前端关闭了连接
+		fmt.Println("前端链接已断开")
+		return
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Println("Error reading response body:", err)
+		return
+	}
+
+	// 生成失败或没有正文时不结算：不扣 AP、不推进回合，前端保留原状态
+	if streamFailed || strings.TrimSpace(fullReply.String()) == "" {
+		fmt.Println("AI 生成失败，跳过回合结算")
+		return
+	}
+
+	// 7. 回合结算，先推送解析出的行动选项，再把权威状态作为最后一个事件推给前端
+	result := turnService.Resolve(&req.GameState, turnCtx, fullReply.String())
+	writeSSEEvent(c, "options", result.Options)
+	writeSSEEvent(c, "state", result)
+}
+
+// writeSSEEvent 推送后端自己生成的事件，content 为 JSON 字符串
+func writeSSEEvent(c *gin.Context, eventType string, payload any) {
+	content, err := json.Marshal(payload)
+	if err != nil {
+		fmt.Println("Error marshaling event:", err)
+		return
+	}
+	data, _ := json.Marshal(AIStreamResponse{Type: eventType, Content: string(content)})
+	c.Writer.WriteString("data: " + string(data) + "\n\n")
+	c.Writer.Flush()
+}
+
+func lastUserInput(messages []Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return messages[i].Content
+		}
+	}
+	return ""
 }
 
 func buildMultiAgentSystemPrompt(summary []string, persona string) string {
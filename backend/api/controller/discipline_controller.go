@@ -0,0 +1,24 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+var disciplineService = service.DisciplineService{}
+
+// GetDiscipline 违纪记录与尚未服完的禁闭
+func GetDiscipline(c *gin.Context) {
+	var req GameStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数格式错误"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    disciplineService.Record(req.GameState),
+	})
+}
@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// DuelRequest Opponent 为图鉴中的对手名，也可以直接给出自定义的 Stats
+type DuelRequest struct {
+	GameState model.GameState     `json:"game_state"`
+	Opponent  string              `json:"opponent"`
+	Stats     *model.DuelistEntry `json:"stats"`
+}
+
+var duelService = service.DuelService{}
+
+// ResolveDuel 结算一场决斗，返回逐回合记录与结算后的存档
+func ResolveDuel(c *gin.Context) {
+	var req DuelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数格式错误"})
+		return
+	}
+	opponent, ok := service.LookupDuelist(req.Opponent, service.SchoolYear(req.GameState.Status))
+	if req.Stats != nil {
+		opponent, ok = *req.Stats, true
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "找不到决斗对手，请提供对手属性"})
+		return
+	}
+	duel := duelService.Duel(&req.GameState, opponent, "")
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"duel":       duel,
+			"game_state": req.GameState,
+		},
+	})
+}
@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+var factionService = service.FactionService{}
+
+func GetFactions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"factions": factionService.Catalog(),
+		},
+	})
+}
+
+// GetStandings 角色在各阵营的声望、地位与已开放的专属内容
+func GetStandings(c *gin.Context) {
+	var req GameStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数格式错误"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"standings": factionService.Standings(req.GameState),
+		},
+	})
+}
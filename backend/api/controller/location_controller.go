@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// LocationCheckRequest Night 为 true 时按宵禁后前往判断
+type LocationCheckRequest struct {
+	GameState model.GameState `json:"game_state"`
+	Location  string          `json:"location" binding:"required"`
+	Night     bool            `json:"night"`
+}
+
+var locationService = service.LocationService{}
+
+func GetLocationGraph(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"locations": locationService.Graph(),
+		},
+	})
+}
+
+// CheckLocation 预先查看前往某地的路线和会违反的校规
+func CheckLocation(c *gin.Context) {
+	var req LocationCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数格式错误"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    locationService.Check(req.GameState, req.Location, req.Night),
+	})
+}
@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+var npcService = service.NPCService{}
+
+func GetNPCRegistry(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"npcs": npcService.Registry(),
+		},
+	})
+}
+
+// GetCast 当前这一周可以登场的原著人物
+func GetCast(c *gin.Context) {
+	var req GameStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数格式错误"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"week": model.WeekOf(req.GameState.Status),
+			"cast": npcService.Cast(req.GameState),
+		},
+	})
+}
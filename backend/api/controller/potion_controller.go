@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+var potionService = service.PotionService{}
+
+func GetPotionRecipes(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"recipes": potionService.Recipes(),
+		},
+	})
+}
+
+// GetAvailableRecipes 当前年级能熬制的配方及还缺的原料
+func GetAvailableRecipes(c *gin.Context) {
+	var req GameStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数格式错误"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"school_year": service.SchoolYear(req.GameState.Status),
+			"recipes":     potionService.Available(req.GameState),
+		},
+	})
+}
@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+var relationshipService = service.RelationshipService{}
+
+// GetRelationshipTimeline 羁绊时间线，?name= 指定只看某个角色
+func GetRelationshipTimeline(c *gin.Context) {
+	var req GameStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数格式错误"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"timeline": relationshipService.Timeline(req.GameState, c.Query("name")),
+		},
+	})
+}
@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+var shopService = service.ShopService{}
+
+func GetShopCatalog(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"shops": shopService.Catalog(),
+		},
+	})
+}
+
+func GetAvailableShops(c *gin.Context) {
+	var req GameStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数格式错误"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"location":    req.GameState.Status.Location,
+			"school_year": service.SchoolYear(req.GameState.Status),
+			"purse":       req.GameState.Status.Purse.String(),
+			"shops":       shopService.Available(req.GameState),
+		},
+	})
+}
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// GameStateRequest 只需要角色存档的查询接口共用的请求体
+type GameStateRequest struct {
+	GameState model.GameState `json:"game_state"`
+}
+
+var spellService = service.SpellService{}
+
+func GetSpellCatalog(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"spells": spellService.Catalog(),
+		},
+	})
+}
+
+func GetLearnableSpells(c *gin.Context) {
+	var req GameStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数格式错误"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"knowledge": req.GameState.Status.Knowledge,
+			"spells":    spellService.Learnable(req.GameState),
+		},
+	})
+}
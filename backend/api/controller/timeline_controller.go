@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+var timelineService = service.TimelineService{}
+
+func GetTimeline(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"events": timelineService.Timeline(),
+		},
+	})
+}
+
+// GetTimelineDrift 存档的世界线偏离原著的程度，以及每件原著事件是否照常发生
+func GetTimelineDrift(c *gin.Context) {
+	var req GameStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数格式错误"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"drift":       timelineService.Drift(req.GameState),
+			"divergences": req.GameState.Divergences,
+		},
+	})
+}
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type EnrolRequest struct {
+	GameState model.GameState `json:"game_state"`
+	Enrolment model.Enrolment `json:"enrolment"`
+}
+
+var timetableService = service.TimetableService{}
+
+func GetTimetable(c *gin.Context) {
+	var req GameStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数格式错误"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"school_year": service.SchoolYear(req.GameState.Status),
+			"enrolment":   req.GameState.Enrolment,
+			"timetable":   timetableService.Timetable(req.GameState),
+		},
+	})
+}
+
+func GetEnrolmentOptions(c *gin.Context) {
+	var req GameStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数格式错误"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    timetableService.Options(req.GameState),
+	})
+}
+
+// Enrol 校验选课后返回新的选课记录与课表，由前端写回存档
+func Enrol(c *gin.Context) {
+	var req EnrolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数格式错误"})
+		return
+	}
+	if err := timetableService.Enrol(&req.GameState, req.Enrolment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"enrolment": req.GameState.Enrolment,
+			"timetable": timetableService.Timetable(req.GameState),
+		},
+	})
+}
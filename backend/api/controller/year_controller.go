@@ -0,0 +1,28 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+var yearService = service.YearService{}
+
+// GetYearbook 历年学年总结，以及本学年或暑假里下一学年的书单
+func GetYearbook(c *gin.Context) {
+	var req GameStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数格式错误"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data": gin.H{
+			"school_year": service.SchoolYear(req.GameState.Status),
+			"yearbook":    req.GameState.Yearbook,
+			"booklist":    yearService.Booklist(req.GameState),
+		},
+	})
+}
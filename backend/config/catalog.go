@@ -0,0 +1,43 @@
+package config
+
+import (
+	_ "embed"
+)
+
+// 规则引擎使用的静态数据表
+
+//go:embed spells.json
+var SpellCatalog []byte
+
+//go:embed shops.json
+var ShopCatalog []byte
+
+//go:embed subjects.json
+var SubjectCatalog []byte
+
+//go:embed quidditch.json
+var QuidditchCatalog []byte
+
+//go:embed duelists.json
+var DuelistCatalog []byte
+
+//go:embed potions.json
+var PotionCatalog []byte
+
+//go:embed npcs.json
+var NPCCatalog []byte
+
+//go:embed locations.json
+var LocationCatalog []byte
+
+//go:embed booklists.json
+var BooklistCatalog []byte
+
+//go:embed careers.json
+var CareerCatalog []byte
+
+//go:embed factions.json
+var FactionCatalog []byte
+
+//go:embed timeline.json
+var TimelineCatalog []byte
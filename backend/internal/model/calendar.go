@@ -0,0 +1,66 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// WeeksPerMonth 游戏内每月固定 4 周，没有第 5 周
+const WeeksPerMonth = 4
+
+// 如 "1992年1月第1周"、"1月第2周"
+var weekPattern = regexp.MustCompile(`^\s*(?:(\d{4})\s*年)?\s*(\d{1,2})\s*月\s*第?\s*(\d)\s*周\s*$`)
+
+// GameWeek 游戏日历上的某一周
+type GameWeek struct {
+	Year  int `json:"year"`
+	Month int `json:"month"`
+	Week  int `json:"week"`
+}
+
+// WeekOf 取出角色当前所处的游戏周
+func WeekOf(status CharacterStatus) GameWeek {
+	return GameWeek{Year: status.CurrentYear, Month: status.CurrentMonth, Week: status.CurrentWeek}
+}
+
+// WeekFromIndex 是 Index 的逆运算
+func WeekFromIndex(index int) GameWeek {
+	return GameWeek{
+		Year:  index / (12 * WeeksPerMonth),
+		Month: index%(12*WeeksPerMonth)/WeeksPerMonth + 1,
+		Week:  index%WeeksPerMonth + 1,
+	}
+}
+
+// Index 把游戏周换算为连续的序号，便于比较先后与计算间隔
+func (w GameWeek) Index() int {
+	return w.Year*12*WeeksPerMonth + (w.Month-1)*WeeksPerMonth + (w.Week - 1)
+}
+
+func (w GameWeek) String() string {
+	return fmt.Sprintf("%d年%d月第%d周", w.Year, w.Month, w.Week)
+}
+
+// ParseWeek 解析 "1992年1月第1周" 或 "1月第1周"，省略年份时取 after 之后最近的那一周
+func ParseWeek(text string, after GameWeek) (GameWeek, error) {
+	match := weekPattern.FindStringSubmatch(text)
+	if match == nil {
+		return GameWeek{}, fmt.Errorf("无法识别的日期「%s」", text)
+	}
+	month, _ := strconv.Atoi(match[2])
+	week, _ := strconv.Atoi(match[3])
+	if month < 1 || month > 12 || week < 1 || week > WeeksPerMonth {
+		return GameWeek{}, errors.New("日期超出范围：每年 12 个月，每月 4 周")
+	}
+	if match[1] != "" {
+		year, _ := strconv.Atoi(match[1])
+		return GameWeek{Year: year, Month: month, Week: week}, nil
+	}
+	parsed := GameWeek{Year: after.Year, Month: month, Week: week}
+	if parsed.Index() <= after.Index() {
+		parsed.Year++
+	}
+	return parsed, nil
+}
@@ -0,0 +1,214 @@
+package model
+
+// SpellEntry 咒语图鉴中的一条，Year 为通常教授的年级，0 表示课堂不教
+type SpellEntry struct {
+	Name         string   `json:"name"`
+	Incantation  string   `json:"incantation"`
+	Aliases      []string `json:"aliases"`
+	Tier         int      `json:"tier"`
+	MinKnowledge int      `json:"min_knowledge"`
+	MPCost       int      `json:"mp_cost"`
+	Year         int      `json:"year"`
+	Desc         string   `json:"desc"`
+}
+
+// Shop 对角巷、霍格莫德等地的商店，MinYear 为允许光顾的最低年级
+type Shop struct {
+	Name    string     `json:"name"`
+	Area    string     `json:"area"`
+	MinYear int        `json:"min_year"`
+	Desc    string     `json:"desc"`
+	Items   []ShopItem `json:"items"`
+}
+
+// ShopItem 商品，价格在 PriceMin 与 PriceMax 之间浮动
+type ShopItem struct {
+	Name     string `json:"name"`
+	Category string `json:"category"` // book | ingredient | pet | equipment | artifact | consumable
+	PriceMin Money  `json:"price_min"`
+	PriceMax Money  `json:"price_max"`
+	MinYear  int    `json:"min_year"`
+	Desc     string `json:"desc"`
+}
+
+// SubjectEntry 霍格沃茨开设的课程，Core 为一至五年级必修课，其余为三年级起的选修课
+type SubjectEntry struct {
+	Name     string   `json:"name"`
+	Core     bool     `json:"core"`
+	FromYear int      `json:"from_year"`
+	ToYear   int      `json:"to_year"`
+	Exam     bool     `json:"exam"`   // 期末是否考试
+	Skill    string   `json:"skill"`  // 对应的技能熟练度
+	Spells   []string `json:"spells"` // 考试会考到的咒语
+
+	Pairs    [][]string  `json:"pairs"`    // 合班的学院，如格兰芬多与斯莱特林合上魔药课
+	Mixed    bool        `json:"mixed"`    // 四个学院一起上的选修课
+	Schedule []ClassSlot `json:"schedule"` // 每周的上课时间
+}
+
+type ClassSlot struct {
+	Day    string `json:"day"`    // 周一 ~ 周五
+	Period string `json:"period"` // 上午 | 下午 | 晚上
+}
+
+// QuidditchCatalog 各学院队的原著阵容与每个赛季固定的赛程
+type QuidditchCatalog struct {
+	Cancelled []string           `json:"cancelled"` // 停赛的学年，如三强争霸赛那一年
+	Fixtures  []QuidditchFixture `json:"fixtures"`
+	Players   []QuidditchPlayer  `json:"players"`
+}
+
+type QuidditchFixture struct {
+	Month int    `json:"month"`
+	Week  int    `json:"week"`
+	Home  string `json:"home"`
+	Away  string `json:"away"`
+}
+
+// QuidditchPlayer 学院队队员，From/To 为在队的赛季起始年份
+type QuidditchPlayer struct {
+	Name     string `json:"name"`
+	House    string `json:"house"`
+	Position string `json:"position"` // 追球手 | 击球手 | 守门员 | 找球手
+	From     int    `json:"from"`
+	To       int    `json:"to"`
+	Rating   int    `json:"rating"` // 与体能同尺度，80 为职业水准
+}
+
+// DuelistEntry NPC 的决斗属性，同一角色按年级可有多条
+type DuelistEntry struct {
+	Name      string             `json:"name"`
+	Aliases   []string           `json:"aliases"`
+	MinYear   int                `json:"min_year"`
+	MaxYear   int                `json:"max_year"`
+	HP        int                `json:"hp"`
+	MP        int                `json:"mp"`
+	Athletics int                `json:"athletics"`
+	Mental    int                `json:"mental"`
+	Knowledge int                `json:"knowledge"`
+	Spells    map[string]float64 `json:"spells"` // 咒语名 -> 熟练度
+	Desc      string             `json:"desc"`
+}
+
+// PotionRecipe 魔药配方，DC 为熬制检定的难度
+type PotionRecipe struct {
+	Name        string         `json:"name"`
+	MinYear     int            `json:"min_year"`
+	DC          float64        `json:"dc"`
+	Yield       int            `json:"yield"` // 一锅成品的瓶数
+	Ingredients []Ingredient   `json:"ingredients"`
+	Effects     map[string]int `json:"effects"` // 合格成品的使用效果
+	Desc        string         `json:"desc"`
+}
+
+type Ingredient struct {
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+}
+
+// NPCEntry 原著人物，SchoolFrom/SchoolTo 为其在霍格沃茨的学年(按开学年份)，均为 0 表示校外人物
+type NPCEntry struct {
+	Name       string    `json:"name"`
+	Aliases    []string  `json:"aliases"`
+	Role       string    `json:"role"`
+	Famous     bool      `json:"famous,omitempty"` // 家喻户晓的名人，不认识也可以直呼其名
+	House      string    `json:"house"`
+	SchoolFrom int       `json:"school_from"`
+	SchoolTo   int       `json:"school_to"`
+	Location   string    `json:"location"` // 在校期间的所在地，校外人物的常驻地
+	Before     string    `json:"before"`   // 来霍格沃茨之前的所在地，为空表示此前不能登场
+	After      string    `json:"after"`    // 离开霍格沃茨之后的所在地，为空表示此后不能登场
+	Born       int       `json:"born,omitempty"`
+	Died       *GameWeek `json:"died,omitempty"`
+	Desc       string    `json:"desc"`
+}
+
+// LocationNode 地点图上的一个节点，Links 为可以直接走到的相邻地点
+type LocationNode struct {
+	Name        string               `json:"name"`
+	Aliases     []string             `json:"aliases"`
+	Region      string               `json:"region"` // 霍格沃茨 | 霍格莫德 | 对角巷 | 伦敦，其余地点为空
+	Floor       string               `json:"floor,omitempty"`
+	Links       []string             `json:"links"`
+	Restriction *LocationRestriction `json:"restriction,omitempty"`
+	NightOK     bool                 `json:"night_ok,omitempty"` // 宵禁后待在这里不算夜游
+	Desc        string               `json:"desc"`
+}
+
+type LocationRestriction struct {
+	Kind    string `json:"kind"` // forbidden 禁止入内 | house 仅限本学院 | staff 仅限教职工 | permission 需要许可 | year 年级不足
+	House   string `json:"house,omitempty"`
+	MinYear int    `json:"min_year,omitempty"`
+	From    int    `json:"from,omitempty"` // 限制生效的学年(按开学年份)，都为 0 表示一直生效
+	To      int    `json:"to,omitempty"`
+	Rule    string `json:"rule"` // 违反时记录的校规
+}
+
+// BookEntry 书单上的课本，Subject 为空表示所有学生都要买
+type BookEntry struct {
+	Title    string `json:"title"`
+	Author   string `json:"author,omitempty"`
+	Subject  string `json:"subject,omitempty"`
+	FromYear int    `json:"from_year"`
+	ToYear   int    `json:"to_year"`
+	From     int    `json:"from,omitempty"` // 只在这几个学年(按开学年份)使用，如洛哈特任教那年，都为 0 表示一直使用
+	To       int    `json:"to,omitempty"`
+}
+
+// CareerEntry 毕业后可以从事的职业，Ranks 按入职年数从低到高排列
+type CareerEntry struct {
+	Name      string             `json:"name"`
+	Employer  string             `json:"employer"`
+	NEWTs     []GradeRequirement `json:"newts"`
+	MinPasses int                `json:"min_passes"` // N.E.W.T. 至少及格的科目数
+	Stats     map[string]int     `json:"stats"`      // 属性门槛，如 {"knowledge": 145}
+	Ranks     []CareerRank       `json:"ranks"`
+	Desc      string             `json:"desc"`
+}
+
+// GradeRequirement N.E.W.T. 单科成绩要求，Subject 为空表示任意一科
+type GradeRequirement struct {
+	Subject  string `json:"subject"`
+	MinGrade string `json:"min_grade"`
+}
+
+// CareerRank 职级，入职满 Years 年晋升，Salary 为每年的薪水(加隆)
+type CareerRank struct {
+	Title  string `json:"title"`
+	Years  int    `json:"years"`
+	Salary int    `json:"salary"`
+}
+
+// FactionEntry 这一时期的阵营，From 为空表示故事开始时就已存在
+type FactionEntry struct {
+	Name    string          `json:"name"`
+	Aliases []string        `json:"aliases"`
+	From    *GameWeek       `json:"from,omitempty"`
+	Desc    string          `json:"desc"`
+	Gates   []FactionGate   `json:"gates"`
+	Actions []FactionAction `json:"actions"`
+}
+
+// FactionGate 只向该阵营声望足够的角色开放的内容，MaxMorality 不为 0 时品性也不能高于该值
+type FactionGate struct {
+	Content     string   `json:"content"`
+	Keywords    []string `json:"keywords"`
+	Min         int      `json:"min"`
+	MaxMorality int      `json:"max_morality,omitempty"`
+}
+
+// FactionAction 玩家指令中出现这些行动时自动调整该阵营的声望
+type FactionAction struct {
+	Keywords []string `json:"keywords"`
+	Delta    int      `json:"delta"`
+}
+
+// CanonEvent 原著时间线上的关键事件，Deaths 为原著中死于这一事件的人物
+type CanonEvent struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Week     GameWeek `json:"week"`
+	Location string   `json:"location"`
+	Desc     string   `json:"desc"`
+	Deaths   []string `json:"deaths,omitempty"`
+}
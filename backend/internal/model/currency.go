@@ -0,0 +1,120 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 巫师货币: 1 加隆 = 17 西可，1 西可 = 29 纳特
+const (
+	KnutsPerSickle    = 29
+	SicklesPerGalleon = 17
+	KnutsPerGalleon   = KnutsPerSickle * SicklesPerGalleon
+)
+
+// Money 以纳特为最小单位的金额，JSON 中为纳特整数，也接受 "2G 5S" 这样的字符串
+type Money int64
+
+var (
+	moneyPartPattern   = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*(galleons?|sickles?|knuts?|加隆|金加隆|西可|银西可|纳特|铜纳特|g|s|k)?`)
+	plainAmountPattern = regexp.MustCompile(`^-?\s*\d+(?:\.\d+)?$`)
+)
+
+func NewMoney(galleons, sickles, knuts int64) Money {
+	return Money(galleons*KnutsPerGalleon + sickles*KnutsPerSickle + knuts)
+}
+
+func Galleons(n int) Money {
+	return Money(int64(n) * KnutsPerGalleon)
+}
+
+// Split 拆成加隆、西可、纳特三部分，负数时各部分均为非负值
+func (m Money) Split() (galleons, sickles, knuts int64) {
+	total := int64(m)
+	if total < 0 {
+		total = -total
+	}
+	return total / KnutsPerGalleon, total % KnutsPerGalleon / KnutsPerSickle, total % KnutsPerSickle
+}
+
+// WholeGalleons 向下取整的加隆数，用于兼容只认整数 gold 的旧字段
+func (m Money) WholeGalleons() int {
+	return int(int64(m) / KnutsPerGalleon)
+}
+
+func (m Money) String() string {
+	galleons, sickles, knuts := m.Split()
+	var parts []string
+	if galleons > 0 {
+		parts = append(parts, fmt.Sprintf("%d加隆", galleons))
+	}
+	if sickles > 0 {
+		parts = append(parts, fmt.Sprintf("%d西可", sickles))
+	}
+	if knuts > 0 || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%d纳特", knuts))
+	}
+	sign := ""
+	if m < 0 {
+		sign = "-"
+	}
+	return sign + strings.Join(parts, "")
+}
+
+// ParseMoney 解析 "7G"、"-2 Sickles 5 Knuts"、"3加隆5西可"、"1.5G" 等写法，小数按纳特四舍五入。
+// 只有整个字符串是一个数字时才按加隆计，混在文字里不带单位的数字视为无法识别
+func ParseMoney(text string) (Money, error) {
+	text = strings.TrimSpace(text)
+	negative := strings.HasPrefix(text, "-")
+	plain := plainAmountPattern.MatchString(text)
+	matches := moneyPartPattern.FindAllStringSubmatch(text, -1)
+	if len(matches) == 0 {
+		return 0, errors.New("无法识别的金额: " + text)
+	}
+	var total Money
+	for _, match := range matches {
+		amount, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return 0, err
+		}
+		var perUnit float64
+		switch unit := strings.ToLower(match[2]); {
+		case unit == "" && !plain:
+			return 0, fmt.Errorf("金额「%s」中的 %s 没有写单位", text, match[1])
+		case unit == "s" || strings.HasPrefix(unit, "sickle") || strings.Contains(unit, "西可"):
+			perUnit = KnutsPerSickle
+		case unit == "k" || strings.HasPrefix(unit, "knut") || strings.Contains(unit, "纳特"):
+			perUnit = 1
+		default:
+			perUnit = KnutsPerGalleon
+		}
+		total += Money(math.Round(amount * perUnit))
+	}
+	if negative {
+		total = -total
+	}
+	return total, nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var knuts int64
+	if err := json.Unmarshal(data, &knuts); err == nil {
+		*m = Money(knuts)
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	parsed, err := ParseMoney(text)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
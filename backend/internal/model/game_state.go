@@ -0,0 +1,565 @@
+package model
+
+import "encoding/json"
+
+type Profile struct {
+	Name        string `json:"name"`
+	Gender      string `json:"gender"`
+	House       string `json:"house"`
+	BloodStatus string `json:"blood_status"`
+	Wand        string `json:"wand"`
+	Patronus    string `json:"patronus"`
+}
+
+// GameState 前端每回合提交的存档快照，结算后由后端回传权威版本
+type GameState struct {
+	Status        CharacterStatus `json:"status"`
+	Profile       Profile         `json:"profile"`
+	Inventory     InventoryMap    `json:"inventory"`
+	Spells        SpellMap        `json:"spells"`
+	Relationships RelationshipMap `json:"relationships"`
+	WorldLog      []string        `json:"world_log"`
+	Turn          int             `json:"turn"` // 已结算的回合数
+
+	PendingOptions []ActionOption `json:"pending_options"` // 上一条回复给出的选项，用于校验玩家的选择
+
+	RNGSeed   uint64        `json:"rng_seed"`            // 角色专属的随机种子
+	RNGCursor uint64        `json:"rng_cursor"`          // 已掷骰次数，同一存档重试会得到相同结果
+	CheckLog  []CheckResult `json:"check_log,omitempty"` // 历次检定记录
+
+	RelationshipHistory map[string][]RelationChange `json:"relationship_history,omitempty"` // 每个角色的羁绊变化轨迹
+
+	HousePoints  HousePointLedger `json:"house_points"`           // 学院分账本与历届学院杯
+	Transactions []Transaction    `json:"transactions,omitempty"` // 收支流水
+
+	Attendance  AttendanceRecord `json:"attendance"`             // 本学年的上课出勤
+	ReportCards []ReportCard     `json:"report_cards,omitempty"` // 历年期末成绩单
+	Enrolment   Enrolment        `json:"enrolment"`              // 选修课与 N.E.W.T. 课程
+	Assignments []Assignment     `json:"assignments,omitempty"`  // 教授布置的作业
+	Quidditch   QuidditchRecord  `json:"quidditch"`              // 学院队身份与历场比赛
+	KnownNames  []string         `json:"known_names"`            // 主角已经知道名字的人物
+	RuleBreaks  []RuleBreak      `json:"rule_breaks,omitempty"`  // 擅闯禁地、夜游等违反校规的记录
+	Violations  []Violation      `json:"violations,omitempty"`   // 被抓到的违纪及处罚
+	Detentions  []Detention      `json:"detentions,omitempty"`   // 已安排的禁闭
+	Effects     []StatusEffect   `json:"effects"`                // 生效中的恶咒、中毒、虚弱等状态
+	SecretRoom  SecretRoom       `json:"requirement_room"`       // 有求必应屋的发现记录与变过的形态
+	Yearbook    []YearRecord     `json:"yearbook,omitempty"`     // 每学年结束时的存档
+	Booklist    *Booklist        `json:"booklist,omitempty"`     // 下一学年的书单
+	Career      *CareerRecord    `json:"career,omitempty"`       // 毕业后从事的职业
+	Goals       []Goal           `json:"goals,omitempty"`        // 玩家定下的长期目标
+
+	Reputation    map[string]int    `json:"reputation"`               // 各阵营对角色的声望，-100 到 100
+	ReputationLog []ReputationEntry `json:"reputation_log,omitempty"` // 声望变化流水
+
+	Divergences []Divergence `json:"divergences,omitempty"` // 与原著时间线的偏离
+
+	TurnContext *TurnContext `json:"turn_context,omitempty"` // 规则引擎本回合的判定，仅由后端生成
+}
+
+// TurnContext 调用模型前由规则引擎做出的判定，叙事必须以此为准
+type TurnContext struct {
+	APCost      int `json:"ap_cost"`      // 本回合所选行动的 AP 消耗
+	APAvailable int `json:"ap_available"` // 本周剩余 AP
+	Overdraft   int `json:"overdraft"`    // 本回合产生的透支点数
+
+	ChosenOptions []ActionOption `json:"chosen_options,omitempty"` // 玩家本回合选中的建议选项
+	Checks        []CheckResult  `json:"checks,omitempty"`         // 本回合已由规则决定的检定结果
+
+	HouseStandings map[string]int  `json:"house_standings"`     // 本学年学院分排名
+	HouseCup       *HouseCupResult `json:"house_cup,omitempty"` // 学年终宴当周公布的学院杯
+
+	Purse string `json:"purse"` // 钱包余额的可读写法，如 12加隆3西可
+
+	ReportCard *ReportCard       `json:"report_card,omitempty"`      // 考试周至终宴期间公布的本学年成绩单
+	Timetable  []TimetableEntry  `json:"timetable,omitempty"`        // 本周课表及合班学院
+	Homework   []Assignment      `json:"homework,omitempty"`         // 尚未完成的作业，含已逾期的
+	Match      *MatchResult      `json:"quidditch_match,omitempty"`  // 本周进行的魁地奇比赛
+	Duel       *DuelResult       `json:"duel,omitempty"`             // 本回合已由规则结算的决斗
+	Brew       *BrewResult       `json:"brew,omitempty"`             // 本回合已由规则结算的魔药熬制
+	Cast       []CastMember      `json:"cast,omitempty"`             // 当前年份可以登场的原著人物
+	KnownNames []string          `json:"known_names,omitempty"`      // 正文可以直呼其名的人物，含公开点过名的同学与教职工
+	Night      bool              `json:"night,omitempty"`            // 玩家本回合在宵禁后行动
+	Detentions []Detention       `json:"detentions,omitempty"`       // 尚未服完的禁闭
+	Room       *RoomVisit        `json:"requirement_room,omitempty"` // 本回合寻找有求必应屋的判定
+	YearEnd    *YearRecord       `json:"year_end,omitempty"`         // 终宴当周结算的学年总结
+	Booklist   *Booklist         `json:"booklist,omitempty"`         // 暑假至开学周寄到的新书单
+	Career     *CareerSummary    `json:"career,omitempty"`           // 毕业后的职业、长期目标与生涯里程碑
+	Factions   []FactionStanding `json:"factions,omitempty"`         // 各阵营的声望与对角色开放的内容
+	Gated      []string          `json:"gated,omitempty"`            // 玩家本回合想接触但声望不足的内容
+	Canon      []CanonStatus     `json:"canon_events,omitempty"`     // 本周及接下来四周的原著事件，含已被改写的
+}
+
+// ForPrompt 发给模型的精简存档，去掉只用于回溯的流水记录
+func (g GameState) ForPrompt() GameState {
+	g.CheckLog = nil
+	g.RelationshipHistory = nil
+	g.HousePoints.Entries = nil
+	g.Transactions = nil
+	g.Assignments = nil
+	g.RuleBreaks = nil
+	g.Violations = nil
+	g.Detentions = nil
+	g.ReputationLog = nil
+	return g
+}
+
+// StateUpdate 模型回复末尾 <state_update> 中的增量更新
+type StateUpdate struct {
+	Status          map[string]json.RawMessage `json:"status"`
+	House           string                     `json:"house"`
+	InventoryEvents []InventoryEvent           `json:"inventory_events"`
+	Spells          SpellMap                   `json:"spells"`
+	Relationships   RelationshipMap            `json:"relationships"`
+	WorldLogAdd     string                     `json:"world_log_add"`
+	HousePoints     []HousePointChange         `json:"house_points"`
+	Transactions    []TransactionChange        `json:"transactions"`
+	Enrolment       *Enrolment                 `json:"enrolment"`
+	Assignments     []AssignmentChange         `json:"assignments"`
+	Quidditch       *QuidditchChange           `json:"quidditch"`
+	KnownNames      []string                   `json:"known_names"`
+	Violations      []ViolationChange          `json:"violations"`
+	Effects         []EffectChange             `json:"effects"`
+	RoomForm        string                     `json:"room_form"`
+	Career          *CareerChange              `json:"career"`
+	Goals           []GoalChange               `json:"goals"`
+	Reputation      []ReputationChange         `json:"reputation"`
+	Divergences     []DivergenceChange         `json:"divergences"`
+}
+
+type InventoryEvent struct {
+	Op        string          `json:"op"` // add | remove | use
+	Item      string          `json:"item"`
+	Desc      string          `json:"desc"`
+	Quantity  int             `json:"quantity,omitempty"` // 增减或使用的数量，缺省为 1；remove 缺省时全部移除
+	Category  string          `json:"category,omitempty"`
+	Stackable *bool           `json:"stackable,omitempty"`
+	Effects   map[string]int  `json:"effects,omitempty"`
+	Price     json.RawMessage `json:"price,omitempty"` // 购买时的单价，由后端按商店目录核价扣款
+	Shop      string          `json:"shop,omitempty"`
+}
+
+// TurnResult 回合结算结果，Warnings 记录被规则引擎驳回或修正的内容
+type TurnResult struct {
+	GameState GameState      `json:"game_state"`
+	Options   []ActionOption `json:"options"`
+	Warnings  []string       `json:"warnings"`
+}
+
+// ActionOption GM 在 Part 2 给出的行动建议，如 "[学业]全勤上课(3AP)"
+type ActionOption struct {
+	Category string `json:"category"` // 学业 | 日常 | 剧情 | 事件 ...
+	Label    string `json:"label"`
+	APCost   int    `json:"ap_cost"`
+	IsEvent  bool   `json:"is_event"` // 事件模式下的应对选项，不消耗 AP
+}
+
+// CheckResult 一次由规则引擎完成的检定
+type CheckResult struct {
+	Turn      int      `json:"turn"`
+	Week      GameWeek `json:"week"`
+	Kind      string   `json:"kind"`      // 检定名称，如 图书馆研读、施法:除你武器
+	Attribute string   `json:"attribute"` // 参与检定的属性
+	Dice      string   `json:"dice"`      // D20 | D100，门槛判定为空
+	Roll      int      `json:"roll"`
+	Modifier  float64  `json:"modifier"`
+	Total     float64  `json:"total"`
+	DC        float64  `json:"dc"`
+	Outcome   string   `json:"outcome"`          // success | near_miss | failure
+	Effect    string   `json:"effect,omitempty"` // 判定带来的数值变化，由 GM 写入 state_update
+}
+
+// RelationChange 一次羁绊等级变化及引起它的回合
+type RelationChange struct {
+	Turn int      `json:"turn"`
+	Week GameWeek `json:"week"`
+	From float64  `json:"from"`
+	To   float64  `json:"to"`
+	Tag  string   `json:"tag"`
+	Desc string   `json:"desc"`
+}
+
+// HousePointChange state_update 中的学院分变化，House 为空时指玩家所在学院
+type HousePointChange struct {
+	House  string `json:"house"`
+	Delta  int    `json:"delta"`
+	Reason string `json:"reason"`
+}
+
+type HousePointLedger struct {
+	Totals  map[string]int    `json:"totals"`  // 本学年各学院积分
+	Entries []HousePointEntry `json:"entries"` // 本学年的加减分流水
+	Cups    []HouseCupResult  `json:"cups"`    // 历届学院杯
+}
+
+type HousePointEntry struct {
+	House     string   `json:"house"`
+	Turn      int      `json:"turn"`
+	Week      GameWeek `json:"week"`
+	Delta     int      `json:"delta"`
+	Reason    string   `json:"reason"`
+	Simulated bool     `json:"simulated"` // 由后端模拟的其他学生加减分
+}
+
+type HouseCupResult struct {
+	SchoolYear string         `json:"school_year"`      // 如 1991-1992
+	Winner     string         `json:"winner"`           // 并列时为「格兰芬多、斯莱特林」
+	Shared     bool           `json:"shared,omitempty"` // 积分并列，学院杯由几个学院共享
+	Totals     map[string]int `json:"totals"`
+}
+
+// TransactionChange state_update 中的一笔收支，Amount 写作 "-2S 5K" 或 "+10G"，纯数字按加隆计
+type TransactionChange struct {
+	Amount json.RawMessage `json:"amount"`
+	Reason string          `json:"reason"`
+}
+
+// Transaction 钱包流水，Balance 为入账后的余额
+type Transaction struct {
+	Turn    int      `json:"turn"`
+	Week    GameWeek `json:"week"`
+	Amount  Money    `json:"amount"`
+	Balance Money    `json:"balance"`
+	Reason  string   `json:"reason"`
+}
+
+// AttendanceRecord 一个学年内的出勤，摸鱼的一周按半次出勤计
+type AttendanceRecord struct {
+	SchoolYear string   `json:"school_year"`
+	Attended   float64  `json:"attended"`
+	Sessions   int      `json:"sessions"`
+	LastWeek   GameWeek `json:"last_week"` // 最近一次记录出勤的周，同一周只记一次
+}
+
+// ReportCard 一个学年的期末成绩单，五年级为 O.W.L.，七年级为 N.E.W.T.
+type ReportCard struct {
+	SchoolYear string         `json:"school_year"` // 如 1991-1992
+	Year       int            `json:"year"`        // 年级
+	Exam       string         `json:"exam"`
+	Week       GameWeek       `json:"week"`
+	Attendance float64        `json:"attendance"` // 出勤率
+	Grades     []SubjectGrade `json:"grades"`
+}
+
+// SubjectGrade 单科成绩，O/E/A 为及格，P/D/T 为不及格
+type SubjectGrade struct {
+	Subject string  `json:"subject"`
+	Grade   string  `json:"grade"`
+	Score   float64 `json:"score"`
+	Passed  bool    `json:"passed"`
+}
+
+// Enrolment 选课记录，二年级末选定三年级起的选修课，O.W.L. 之后选定 N.E.W.T. 课程
+type Enrolment struct {
+	Electives    []string `json:"electives"`
+	NEWTSubjects []string `json:"newt_subjects"`
+}
+
+// TimetableEntry 课表中的一节课
+type TimetableEntry struct {
+	Subject string    `json:"subject"`
+	Slot    ClassSlot `json:"slot"`
+	Houses  []string  `json:"houses"` // 一起上课的学院
+	Level   string    `json:"level"`  // O.W.L. | N.E.W.T.
+}
+
+// AssignmentChange state_update 中布置或完成的作业，Title 与已有作业同名时视为更新
+type AssignmentChange struct {
+	Subject    string `json:"subject"`
+	Title      string `json:"title"`
+	Due        string `json:"due"`          // 如 "1月第1周"
+	DueInWeeks int    `json:"due_in_weeks"` // 没写 due 时按几周后到期
+	Done       bool   `json:"done"`
+}
+
+// Assignment 一份作业，逾期未交时由后端扣分并标记 overdue
+type Assignment struct {
+	Subject  string   `json:"subject"`
+	Title    string   `json:"title"`
+	Assigned GameWeek `json:"assigned"`
+	Due      GameWeek `json:"due"`
+	Status   string   `json:"status"`            // pending | done | overdue
+	Penalty  string   `json:"penalty,omitempty"` // 逾期的处罚
+}
+
+// QuidditchChange state_update 中加入或退出学院队
+type QuidditchChange struct {
+	Join  string `json:"join"` // 位置：追球手 | 击球手 | 守门员 | 找球手
+	Leave bool   `json:"leave"`
+}
+
+type QuidditchRecord struct {
+	Position string        `json:"position"` // 玩家在学院队的位置，未入队为空
+	Results  []MatchResult `json:"results"`
+}
+
+// MatchResult 一场由后端模拟的魁地奇比赛
+type MatchResult struct {
+	Season         string   `json:"season"` // 如 1991-1992
+	Week           GameWeek `json:"week"`
+	Home           string   `json:"home"`
+	Away           string   `json:"away"`
+	HomeScore      int      `json:"home_score"`
+	AwayScore      int      `json:"away_score"`
+	Winner         string   `json:"winner"` // 平局时为空
+	SnitchCaughtBy string   `json:"snitch_caught_by"`
+	PlayerPlayed   bool     `json:"player_played"` // 玩家是否代表学院出场
+	Highlights     []string `json:"highlights"`
+}
+
+// DuelResult 一场由规则引擎逐回合结算的决斗，GM 只负责描写
+type DuelResult struct {
+	Opponent   string      `json:"opponent"`
+	Winner     string      `json:"winner"` // player | opponent | draw
+	Rounds     []DuelRound `json:"rounds"`
+	PlayerHP   int         `json:"player_hp"`
+	PlayerMP   int         `json:"player_mp"`
+	OpponentHP int         `json:"opponent_hp"`
+	OpponentMP int         `json:"opponent_mp"`
+}
+
+// DuelRound 决斗中的一次出手
+type DuelRound struct {
+	Round   int     `json:"round"`
+	Actor   string  `json:"actor"`
+	Spell   string  `json:"spell"`
+	Attack  float64 `json:"attack"`
+	Defense float64 `json:"defense"`
+	Hit     bool    `json:"hit"`
+	Effect  string  `json:"effect"`
+}
+
+// BrewResult 一次熬制的结果，原料不足或条件不满足时 Quality 为空、Note 说明原因
+type BrewResult struct {
+	Recipe   string         `json:"recipe"`
+	Quality  string         `json:"quality,omitempty"` // perfect 优质 | standard 合格 | poor 劣质 | failed 失败
+	Check    *CheckResult   `json:"check,omitempty"`
+	Consumed []Ingredient   `json:"consumed,omitempty"`
+	Missing  []Ingredient   `json:"missing,omitempty"`
+	Potion   string         `json:"potion,omitempty"` // 放入物品栏的成品名
+	Quantity int            `json:"quantity,omitempty"`
+	Effects  map[string]int `json:"effects,omitempty"`
+	Note     string         `json:"note,omitempty"`
+}
+
+// CastMember 当前可以登场的原著人物及其所在地
+type CastMember struct {
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	House    string `json:"house,omitempty"`
+	Location string `json:"location"`
+}
+
+// RuleBreak 一次违反校规的行为，由地点规则在结算时记录
+type RuleBreak struct {
+	Turn     int      `json:"turn"`
+	Week     GameWeek `json:"week"`
+	Location string   `json:"location"`
+	Rule     string   `json:"rule"`
+}
+
+// ViolationChange GM 叙述玩家违纪被抓时填写，未写的严重程度与扣分按校规表决定
+type ViolationChange struct {
+	Rule          string `json:"rule"`
+	Severity      string `json:"severity"` // minor 轻微 | moderate 一般 | severe 严重
+	CaughtBy      string `json:"caught_by"`
+	Points        int    `json:"points"`         // 扣除的学院分，正数
+	Detention     string `json:"detention"`      // 禁闭内容，写了即安排禁闭
+	DetentionWeek string `json:"detention_week"` // 禁闭日期，如 10月第3周，缺省为下一周
+}
+
+type Violation struct {
+	Turn     int      `json:"turn"`
+	Week     GameWeek `json:"week"`
+	Rule     string   `json:"rule"`
+	Severity string   `json:"severity"`
+	CaughtBy string   `json:"caught_by"`
+	Location string   `json:"location"`
+	Points   int      `json:"points"`
+}
+
+type Detention struct {
+	Week       GameWeek `json:"week"`
+	Supervisor string   `json:"supervisor"`
+	Task       string   `json:"task"`
+	Reason     string   `json:"reason"`
+	Status     string   `json:"status"` // pending | served | missed
+}
+
+// StatusEffect 持续数周的状态，Modifiers 在生效期间加在属性上，Tick 每过一周结算一次
+type StatusEffect struct {
+	Name           string         `json:"name"`
+	Source         string         `json:"source"`
+	Modifiers      map[string]int `json:"modifiers,omitempty"`
+	Tick           map[string]int `json:"tick,omitempty"`
+	RemainingWeeks int            `json:"remaining_weeks"` // 0 表示直到治愈为止
+	Cure           string         `json:"cure,omitempty"`  // 治愈条件：使用的物品或前往的地点，如 治疗疖子药水、校医院
+	Since          GameWeek       `json:"since"`
+	Applied        map[string]int `json:"applied,omitempty"` // 实际加到属性上的数值，移除时按此还原
+}
+
+// EffectChange GM 叙述中角色中咒、中毒或被治愈时填写
+type EffectChange struct {
+	Name      string         `json:"name"`
+	Source    string         `json:"source"`
+	Modifiers map[string]int `json:"modifiers"`
+	Tick      map[string]int `json:"tick"`
+	Weeks     int            `json:"weeks"`
+	Cure      string         `json:"cure"`
+	Remove    bool           `json:"remove"`
+}
+
+// SecretRoom 有求必应屋只有在条件满足时才会向角色显现，发现之后角色随时可以再用
+type SecretRoom struct {
+	Discovered     bool       `json:"discovered"`
+	DiscoveredAt   *GameWeek  `json:"discovered_at,omitempty"`
+	DiscoveredTurn int        `json:"discovered_turn,omitempty"`
+	Forms          []RoomForm `json:"forms,omitempty"`
+}
+
+// RoomForm 有求必应屋按需求变成过的样子
+type RoomForm struct {
+	Need  string   `json:"need"`
+	Form  string   `json:"form"` // 如 决斗练习室、堆满杂物的储藏室
+	Since GameWeek `json:"since"`
+	Uses  int      `json:"uses"`
+}
+
+// RoomVisit 本回合寻找有求必应屋的结果，Open 为 false 时墙上不会出现门
+type RoomVisit struct {
+	Open       bool         `json:"open"`
+	Discovery  bool         `json:"discovery,omitempty"` // 本回合第一次发现
+	Need       string       `json:"need,omitempty"`
+	Check      *CheckResult `json:"check,omitempty"`
+	Reason     string       `json:"reason,omitempty"`
+	KnownForms []string     `json:"known_forms,omitempty"`
+}
+
+// YearRecord 学年终宴时归档的一年，MaxMP 为成长之后的魔力上限
+type YearRecord struct {
+	SchoolYear  string   `json:"school_year"` // 如 1991-1992
+	Year        int      `json:"year"`        // 年级
+	Week        GameWeek `json:"week"`
+	MaxMPBefore int      `json:"max_mp_before"`
+	MaxMP       int      `json:"max_mp"`
+	Knowledge   int      `json:"knowledge"`
+	Mental      int      `json:"mental"`
+	Athletics   int      `json:"athletics"`
+	Charm       int      `json:"charm"`
+	HouseCup    string   `json:"house_cup,omitempty"` // 当年学院杯得主
+	Exam        string   `json:"exam,omitempty"`
+	Passed      int      `json:"passed"` // 及格科目数
+	Failed      int      `json:"failed"`
+	Attendance  float64  `json:"attendance"`
+	Violations  int      `json:"violations"`
+	Graduated   bool     `json:"graduated,omitempty"`
+}
+
+// Booklist 暑假里随通知书寄来的新学年书单
+type Booklist struct {
+	SchoolYear string      `json:"school_year"`
+	Year       int         `json:"year"`
+	Books      []BookEntry `json:"books"`
+}
+
+// CareerRecord 毕业后的职业，薪水在每个入职周年发放
+type CareerRecord struct {
+	Name       string            `json:"name"`
+	Employer   string            `json:"employer"`
+	Rank       string            `json:"rank"`
+	Since      GameWeek          `json:"since"`
+	Left       *GameWeek         `json:"left,omitempty"` // 离职的周，为空表示仍在职
+	PaidYears  int               `json:"paid_years"`     // 已发放薪水的年数
+	Milestones []CareerMilestone `json:"milestones"`
+}
+
+// CareerMilestone 入职、晋升、离职以及 GM 记下的生涯大事
+type CareerMilestone struct {
+	Week  GameWeek `json:"week"`
+	Event string   `json:"event"`
+}
+
+// Goal 玩家的长期目标，Progress 为 0-100
+type Goal struct {
+	Name     string    `json:"name"`
+	Progress int       `json:"progress"`
+	Since    GameWeek  `json:"since"`
+	Done     *GameWeek `json:"done,omitempty"`
+}
+
+// CareerChange 玩家入职、离职或在职业上取得成就时填写
+type CareerChange struct {
+	Join      string `json:"join"`
+	Leave     bool   `json:"leave"`
+	Milestone string `json:"milestone"`
+}
+
+// GoalChange 新立目标或推进目标，Progress 为本回合增加的进度
+type GoalChange struct {
+	Name     string `json:"name"`
+	Progress int    `json:"progress"`
+	Drop     bool   `json:"drop"`
+}
+
+// CareerSummary 毕业后每回合交给 GM 的生涯概况
+type CareerSummary struct {
+	Career     *CareerRecord     `json:"career,omitempty"`
+	Salary     int               `json:"salary,omitempty"`     // 当前职级的年薪(加隆)
+	NextRank   string            `json:"next_rank,omitempty"`  // 下一个职级
+	YearsTo    int               `json:"years_to,omitempty"`   // 距离下次晋升的年数
+	Eligible   []string          `json:"eligible,omitempty"`   // 尚未就业时可以入职的职业
+	Goals      []Goal            `json:"goals,omitempty"`      // 尚未完成的长期目标
+	Milestones []CareerMilestone `json:"milestones,omitempty"` // 最近的生涯里程碑
+}
+
+// ReputationChange GM 叙述中角色的所作所为让某个阵营改变看法时填写
+type ReputationChange struct {
+	Faction string `json:"faction"`
+	Delta   int    `json:"delta"`
+	Reason  string `json:"reason"`
+}
+
+// ReputationEntry 声望流水，Score 为变化后的声望
+type ReputationEntry struct {
+	Turn    int      `json:"turn"`
+	Week    GameWeek `json:"week"`
+	Faction string   `json:"faction"`
+	Delta   int      `json:"delta"`
+	Score   int      `json:"score"`
+	Reason  string   `json:"reason"`
+}
+
+// FactionStanding 角色在某个阵营中的地位
+type FactionStanding struct {
+	Faction  string   `json:"faction"`
+	Score    int      `json:"score"`
+	Standing string   `json:"standing"` // 敌对 | 冷淡 | 中立 | 友善 | 信任 | 核心
+	Unlocked []string `json:"unlocked,omitempty"`
+	Locked   []string `json:"locked,omitempty"`
+}
+
+// Divergence 角色改写了一件原著事件，Prevented 表示事件不再发生
+type Divergence struct {
+	Event     string   `json:"event"` // 原著事件 ID
+	Name      string   `json:"name"`
+	How       string   `json:"how"`
+	Prevented bool     `json:"prevented"`
+	Turn      int      `json:"turn"`
+	Week      GameWeek `json:"week"`
+}
+
+// DivergenceChange 角色的行动改变或阻止了原著事件时填写
+type DivergenceChange struct {
+	Event     string `json:"event"` // 原著事件 ID 或名称
+	How       string `json:"how"`
+	Prevented bool   `json:"prevented"`
+}
+
+// CanonStatus 原著事件在当前世界线中的状态
+type CanonStatus struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Week     GameWeek `json:"week"`
+	Location string   `json:"location,omitempty"`
+	Status   string   `json:"status"` // canon 照原著发生 | altered 已被改写 | prevented 不再发生
+	How      string   `json:"how,omitempty"`
+}
@@ -28,6 +28,32 @@ type Character struct {
 	Relationships RelationshipMap `gorm:"type:json;serializer:json" json:"relationships"` // 羁绊
 	Inventory     InventoryMap    `gorm:"type:json;serializer:json" json:"inventory"`     // 物品栏
 	WorldLog      []string        `gorm:"type:json;serializer:json" json:"world_log"`     // 世界线变动
+	Turn          int             `json:"turn"`                                           // 已结算回合数
+	RNGSeed       uint64          `json:"rng_seed"`                                       // 检定用随机种子
+	RNGCursor     uint64          `json:"rng_cursor"`
+	CheckLog      []CheckResult   `gorm:"type:json;serializer:json" json:"check_log"` // 检定记录
+
+	RelationshipHistory map[string][]RelationChange `gorm:"type:json;serializer:json" json:"relationship_history"` // 羁绊变化轨迹
+	HousePoints         HousePointLedger            `gorm:"type:json;serializer:json" json:"house_points"`         // 学院分账本
+	Transactions        []Transaction               `gorm:"type:json;serializer:json" json:"transactions"`         // 收支流水
+	Attendance          AttendanceRecord            `gorm:"type:json;serializer:json" json:"attendance"`           // 本学年出勤
+	ReportCards         []ReportCard                `gorm:"type:json;serializer:json" json:"report_cards"`         // 历年成绩单
+	Enrolment           Enrolment                   `gorm:"type:json;serializer:json" json:"enrolment"`            // 选课
+	Assignments         []Assignment                `gorm:"type:json;serializer:json" json:"assignments"`          // 作业
+	Quidditch           QuidditchRecord             `gorm:"type:json;serializer:json" json:"quidditch"`            // 魁地奇
+	KnownNames          []string                    `gorm:"type:json;serializer:json" json:"known_names"`          // 已知人名
+	RuleBreaks          []RuleBreak                 `gorm:"type:json;serializer:json" json:"rule_breaks"`          // 违反校规记录
+	Violations          []Violation                 `gorm:"type:json;serializer:json" json:"violations"`           // 违纪处罚
+	Detentions          []Detention                 `gorm:"type:json;serializer:json" json:"detentions"`           // 禁闭
+	Effects             []StatusEffect              `gorm:"type:json;serializer:json" json:"effects"`              // 持续状态
+	SecretRoom          SecretRoom                  `gorm:"type:json;serializer:json" json:"requirement_room"`     // 有求必应屋
+	Yearbook            []YearRecord                `gorm:"type:json;serializer:json" json:"yearbook"`             // 历年学年总结
+	Booklist            *Booklist                   `gorm:"type:json;serializer:json" json:"booklist"`             // 下一学年书单
+	Career              *CareerRecord               `gorm:"type:json;serializer:json" json:"career"`               // 毕业后的职业
+	Goals               []Goal                      `gorm:"type:json;serializer:json" json:"goals"`                // 长期目标
+	Reputation          map[string]int              `gorm:"type:json;serializer:json" json:"reputation"`           // 阵营声望
+	ReputationLog       []ReputationEntry           `gorm:"type:json;serializer:json" json:"reputation_log"`       // 声望流水
+	Divergences         []Divergence                `gorm:"type:json;serializer:json" json:"divergences"`          // 与原著的偏离
 
 	Summary              []string  `gorm:"type:json;serializer:json" json:"summary"`
 	LastSummaryTimestamp int64     `json:"last_summary_timestamp"`
@@ -38,10 +64,13 @@ type CharacterStatus struct {
 	HP    int `json:"hp"`     // 生命值
 	MP    int `json:"mp"`     // 魔力值
 	MaxMP int `json:"max_mp"` // 最大魔力
-	Gold  int `json:"gold"`   // 金加隆
+	Gold  int `json:"gold"`   // 金加隆，钱包中的整加隆数，由 Purse 同步
 	AP    int `json:"ap"`     // 行动力
 	MaxAP int `json:"max_ap"`
 
+	Overdraft int   `json:"overdraft"` // 本周已透支的行动力，跨周时从下周 AP 上限中扣除
+	Purse     Money `json:"purse"`     // 钱包余额，单位纳特
+
 	Knowledge int `json:"knowledge"` // 知识值
 	Athletics int `json:"athletics"` // 体质值
 	Charm     int `json:"charm"`     // 魅力值
@@ -78,13 +107,24 @@ type (
 type (
 	InventoryMap  map[string]InventoryInfo
 	InventoryInfo struct {
-		Desc string `json:"desc"` // 物品描述
+		Desc      string         `json:"desc"`                // 物品描述
+		Quantity  int            `json:"quantity,omitempty"`  // 数量，旧存档为 0 时按 1 件计
+		Category  string         `json:"category,omitempty"`  // book | ingredient | pet | equipment | artifact | consumable
+		Stackable bool           `json:"stackable,omitempty"` // 可堆叠的物品按数量累加
+		Effects   map[string]int `json:"effects,omitempty"`   // 使用时对状态的影响，如 {"hp": 20}
 	}
 )
 
+// Count 物品数量
+func (info InventoryInfo) Count() int {
+	return max(info.Quantity, 1)
+}
+
 type Message struct {
 	gorm.Model
 	CharacterID uint   `gorm:"index;not null" json:"character_id"`
 	Role        string `gorm:"size:20;not null" json:"role"`
 	Content     string `gorm:"type:text;not null" json:"content"`
+
+	Options []ActionOption `gorm:"type:json;serializer:json" json:"options"` // GM 回复中解析出的行动选项
 }
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+const (
+	BaseWeeklyAP   = 7 // 每周标准行动力
+	MaxOverdraftAP = 3 // 每周最多可透支的行动力
+)
+
+// 匹配行动标注的消耗，如 "全勤上课(3AP)"、"社交八卦（1AP）"、"消耗 [2] AP"
+var apCostPattern = regexp.MustCompile(`[(（]\s*(\d+)\s*[Aa][Pp]\s*[)）]|消耗\s*\[?\s*(\d+)\s*\]?\s*[Aa][Pp]`)
+
+// ParseAPCost 汇总玩家指令中所有行动标注的 AP 消耗
+func ParseAPCost(input string) int {
+	total := 0
+	for _, match := range apCostPattern.FindAllStringSubmatch(input, -1) {
+		cost, err := strconv.Atoi(match[1] + match[2])
+		if err != nil {
+			continue
+		}
+		total += cost
+	}
+	return total
+}
+
+// usesAP 只有周常模式消耗 AP，序章、事件模式和毕业后都不计 AP
+func usesAP(status model.CharacterStatus) bool {
+	return status.GameMode == "weekly"
+}
+
+// CheckAPBudget 校验本回合消耗是否超出本周剩余 AP 与可透支额度
+func CheckAPBudget(status model.CharacterStatus, cost int) error {
+	overdraftLeft := MaxOverdraftAP - status.Overdraft
+	if overdraftLeft < 0 {
+		overdraftLeft = 0
+	}
+	if cost > status.AP+overdraftLeft {
+		return fmt.Errorf("行动力不足：本回合行动需要 %dAP，本周剩余 %dAP，最多还能透支 %dAP", cost, status.AP, overdraftLeft)
+	}
+	return nil
+}
+
+// spendAP 扣除行动力，不足的部分记为透支，返回本次新增的透支点数
+func spendAP(status *model.CharacterStatus, cost int) int {
+	status.AP -= cost
+	if status.AP >= 0 {
+		return 0
+	}
+	overdraft := -status.AP
+	status.Overdraft += overdraft
+	status.AP = 0
+	return overdraft
+}
+
+// rolloverAP 进入新的一周，每透支 1 点，新一周的 AP 上限降低 1 点
+func rolloverAP(status *model.CharacterStatus) {
+	status.MaxAP = BaseWeeklyAP - status.Overdraft
+	status.AP = status.MaxAP
+	status.Overdraft = 0
+}
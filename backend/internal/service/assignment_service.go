@@ -0,0 +1,122 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+// 作业状态
+const (
+	AssignmentPending = "pending"
+	AssignmentDone    = "done"
+	AssignmentOverdue = "overdue"
+)
+
+const (
+	defaultAssignmentWeeks = 2  // 没写截止日期的作业两周后到期
+	overduePointPenalty    = 10 // 逾期未交扣的学院分
+	maxAssignments         = 50
+)
+
+type AssignmentService struct{}
+
+// Outstanding 尚未完成的作业，含已经逾期的
+func (s *AssignmentService) Outstanding(state model.GameState) []model.Assignment {
+	outstanding := []model.Assignment{}
+	for _, assignment := range state.Assignments {
+		if assignment.Status != AssignmentDone {
+			outstanding = append(outstanding, assignment)
+		}
+	}
+	return outstanding
+}
+
+// applyAssignments 布置新作业或把已有作业标记为完成
+func applyAssignments(state *model.GameState, changes []model.AssignmentChange) []string {
+	var warnings []string
+	now := model.WeekOf(state.Status)
+	for _, change := range changes {
+		if index := findAssignment(state.Assignments, change); index != -1 {
+			if change.Done {
+				state.Assignments[index].Status = AssignmentDone
+			}
+			continue
+		}
+		if alreadyDone(state.Assignments, change, now) {
+			continue
+		}
+		if change.Title == "" {
+			warnings = append(warnings, fmt.Sprintf("找不到要更新的%s作业", change.Subject))
+			continue
+		}
+		due := model.WeekFromIndex(now.Index() + defaultAssignmentWeeks)
+		if change.DueInWeeks > 0 {
+			due = model.WeekFromIndex(now.Index() + change.DueInWeeks)
+		}
+		if change.Due != "" {
+			parsed, err := model.ParseWeek(change.Due, now)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("作业「%s」的截止日期%s，按 %s 记录", change.Title, err, due))
+			} else {
+				due = parsed
+			}
+		}
+		status := AssignmentPending
+		if change.Done {
+			status = AssignmentDone
+		}
+		state.Assignments = append(state.Assignments, model.Assignment{
+			Subject:  change.Subject,
+			Title:    change.Title,
+			Assigned: now,
+			Due:      due,
+			Status:   status,
+		})
+	}
+	if len(state.Assignments) > maxAssignments {
+		state.Assignments = state.Assignments[len(state.Assignments)-maxAssignments:]
+	}
+	return warnings
+}
+
+// findAssignment 在未完成(含逾期)的作业中按标题匹配；只写了科目时匹配该科最早的一份。
+// 已完成的作业不参与匹配，每年都会布置的同名作业(如假期作业)会作为新作业记录
+func findAssignment(assignments []model.Assignment, change model.AssignmentChange) int {
+	for i, assignment := range assignments {
+		if assignment.Status == AssignmentDone {
+			continue
+		}
+		if change.Title != "" && assignment.Title == change.Title ||
+			change.Title == "" && change.Subject != "" && assignment.Subject == change.Subject {
+			return i
+		}
+	}
+	return -1
+}
+
+// alreadyDone 模型重复报告本学年一份已经交掉的作业
+func alreadyDone(assignments []model.Assignment, change model.AssignmentChange, now model.GameWeek) bool {
+	return change.Done && slices.ContainsFunc(assignments, func(assignment model.Assignment) bool {
+		return assignment.Status == AssignmentDone && assignment.Title == change.Title &&
+			schoolYearLabel(assignment.Assigned) == schoolYearLabel(now)
+	})
+}
+
+// settleOverdueAssignments 截止周过去仍未完成的作业标记为逾期并扣学院分
+func settleOverdueAssignments(state *model.GameState, week model.GameWeek) {
+	house := NormalizeHouse(state.Profile.House)
+	for i := range state.Assignments {
+		assignment := &state.Assignments[i]
+		if assignment.Status != AssignmentPending || assignment.Due.Index() >= week.Index() {
+			continue
+		}
+		assignment.Status = AssignmentOverdue
+		assignment.Penalty = "教授不满"
+		if house != "" {
+			assignment.Penalty = fmt.Sprintf("%s扣 %d 分", house, overduePointPenalty)
+			recordHousePoints(state, week, house, -overduePointPenalty, fmt.Sprintf("%s作业「%s」逾期未交", assignment.Subject, assignment.Title), false)
+		}
+	}
+}
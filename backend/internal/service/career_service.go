@@ -0,0 +1,300 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/config"
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+const (
+	gradeOrder       = "OEAPDT" // 从高到低
+	maxGoals         = 10
+	maxMilestones    = 50
+	summaryMilestone = 5 // 交给 GM 的最近里程碑条数
+)
+
+var careerCatalog = mustLoadCatalog[[]model.CareerEntry]("职业目录", config.CareerCatalog)
+
+var attributeNames = map[string]string{
+	"knowledge": "学识",
+	"mental":    "心智",
+	"athletics": "体能",
+	"charm":     "魅力",
+	"morality":  "品性",
+}
+
+type CareerService struct{}
+
+// CareerOption 职业及角色尚未满足的入职要求
+type CareerOption struct {
+	model.CareerEntry
+	Gaps []string `json:"gaps"`
+}
+
+// Catalog 全部职业
+func (s *CareerService) Catalog() []model.CareerEntry {
+	return careerCatalog
+}
+
+// Eligibility 按 N.E.W.T. 成绩与属性逐个列出每个职业还差什么，Gaps 为空即可入职
+func (s *CareerService) Eligibility(state model.GameState) []CareerOption {
+	options := []CareerOption{}
+	for _, entry := range careerCatalog {
+		options = append(options, CareerOption{CareerEntry: entry, Gaps: careerGaps(&state, entry)})
+	}
+	return options
+}
+
+// graduated 七年级的学年已经在终宴归档
+func graduated(state *model.GameState) bool {
+	return slices.ContainsFunc(state.Yearbook, func(record model.YearRecord) bool { return record.Graduated })
+}
+
+func newtReportCard(state *model.GameState) *model.ReportCard {
+	for i := len(state.ReportCards) - 1; i >= 0; i-- {
+		if state.ReportCards[i].Year == NEWTYear {
+			return &state.ReportCards[i]
+		}
+	}
+	return nil
+}
+
+// careerGaps 角色尚未满足的入职要求
+func careerGaps(state *model.GameState, entry model.CareerEntry) []string {
+	gaps := []string{}
+	card := newtReportCard(state)
+	if card == nil && (len(entry.NEWTs) > 0 || entry.MinPasses > 0) {
+		gaps = append(gaps, "没有 N.E.W.T. 成绩")
+	}
+	if card != nil {
+		for _, requirement := range entry.NEWTs {
+			if !meetsGrade(card, requirement) {
+				subject := requirement.Subject
+				if subject == "" {
+					subject = "任意一科"
+				}
+				gaps = append(gaps, fmt.Sprintf("%s N.E.W.T. 需要 %s 以上", subject, requirement.MinGrade))
+			}
+		}
+		passed := 0
+		for _, grade := range card.Grades {
+			if grade.Passed {
+				passed++
+			}
+		}
+		if passed < entry.MinPasses {
+			gaps = append(gaps, fmt.Sprintf("需要 %d 门 N.E.W.T. 及格，目前 %d 门", entry.MinPasses, passed))
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(entry.Stats)) {
+		if value := attributeValue(state.Status, key); value < entry.Stats[key] {
+			gaps = append(gaps, fmt.Sprintf("%s需要 %d，目前 %d", attributeNames[key], entry.Stats[key], value))
+		}
+	}
+	return gaps
+}
+
+func meetsGrade(card *model.ReportCard, requirement model.GradeRequirement) bool {
+	need := strings.Index(gradeOrder, requirement.MinGrade)
+	for _, grade := range card.Grades {
+		if requirement.Subject != "" && grade.Subject != requirement.Subject {
+			continue
+		}
+		if index := strings.Index(gradeOrder, grade.Grade); index != -1 && index <= need {
+			return true
+		}
+	}
+	return false
+}
+
+// LookupCareer 按职业名或雇主查找
+func LookupCareer(name string) (model.CareerEntry, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return model.CareerEntry{}, false
+	}
+	for _, entry := range careerCatalog {
+		if entry.Name == name || entry.Employer == name {
+			return entry, true
+		}
+	}
+	for _, entry := range careerCatalog {
+		if strings.Contains(name, entry.Name) || strings.Contains(name, entry.Employer) {
+			return entry, true
+		}
+	}
+	return model.CareerEntry{}, false
+}
+
+// rankAt 入职满 years 年时的职级
+func rankAt(entry model.CareerEntry, years int) int {
+	index := 0
+	for i, rank := range entry.Ranks {
+		if rank.Years <= years {
+			index = i
+		}
+	}
+	return index
+}
+
+// applyCareerChange 入职前校验毕业与入职要求，离职和生涯大事记入里程碑
+func applyCareerChange(state *model.GameState, change *model.CareerChange) []string {
+	if change == nil {
+		return nil
+	}
+	var warnings []string
+	week := model.WeekOf(state.Status)
+	if change.Leave || change.Join != "" {
+		if career := state.Career; career != nil && career.Left == nil {
+			career.Left = &week
+			addMilestone(state, fmt.Sprintf("离开%s，卸任%s", career.Employer, career.Rank))
+		}
+	}
+	if change.Join != "" {
+		entry, ok := LookupCareer(change.Join)
+		switch {
+		case !ok:
+			warnings = append(warnings, fmt.Sprintf("职业目录中没有「%s」", change.Join))
+		case !graduated(state):
+			warnings = append(warnings, fmt.Sprintf("毕业之后才能入职%s", entry.Name))
+		default:
+			if gaps := careerGaps(state, entry); len(gaps) > 0 {
+				warnings = append(warnings, fmt.Sprintf("不满足%s的入职要求：%s", entry.Name, strings.Join(gaps, "；")))
+				break
+			}
+			state.Career = &model.CareerRecord{Name: entry.Name, Employer: entry.Employer, Rank: entry.Ranks[0].Title, Since: week}
+			addMilestone(state, fmt.Sprintf("进入%s，担任%s", entry.Employer, entry.Ranks[0].Title))
+		}
+	}
+	if change.Milestone != "" {
+		if state.Career == nil || state.Career.Left != nil {
+			warnings = append(warnings, fmt.Sprintf("角色没有在职，忽略里程碑「%s」", change.Milestone))
+		} else {
+			addMilestone(state, change.Milestone)
+		}
+	}
+	return warnings
+}
+
+func addMilestone(state *model.GameState, event string) {
+	career := state.Career
+	career.Milestones = append(career.Milestones, model.CareerMilestone{Week: model.WeekOf(state.Status), Event: event})
+	if len(career.Milestones) > maxMilestones {
+		career.Milestones = career.Milestones[len(career.Milestones)-maxMilestones:]
+	}
+}
+
+// applyGoalChanges 新立、推进或放弃长期目标，进度到 100 视为达成
+func applyGoalChanges(state *model.GameState, changes []model.GoalChange) []string {
+	var warnings []string
+	week := model.WeekOf(state.Status)
+	for _, change := range changes {
+		name := strings.TrimSpace(change.Name)
+		if name == "" {
+			continue
+		}
+		index := slices.IndexFunc(state.Goals, func(goal model.Goal) bool { return goal.Name == name })
+		switch {
+		case change.Drop:
+			if index != -1 {
+				state.Goals = slices.Delete(state.Goals, index, index+1)
+			}
+			continue
+		case index == -1:
+			if activeGoals(state) >= maxGoals {
+				warnings = append(warnings, fmt.Sprintf("长期目标已达 %d 个上限，忽略「%s」", maxGoals, name))
+				continue
+			}
+			state.Goals = append(state.Goals, model.Goal{Name: name, Since: week})
+			index = len(state.Goals) - 1
+		}
+		goal := &state.Goals[index]
+		if goal.Done != nil {
+			continue
+		}
+		goal.Progress = min(max(goal.Progress+change.Progress, 0), 100)
+		if goal.Progress == 100 {
+			goal.Done = &week
+			if state.Career != nil && state.Career.Left == nil {
+				addMilestone(state, "达成目标："+goal.Name)
+			}
+		}
+	}
+	return warnings
+}
+
+func activeGoals(state *model.GameState) int {
+	count := 0
+	for _, goal := range state.Goals {
+		if goal.Done == nil {
+			count++
+		}
+	}
+	return count
+}
+
+// payCareer 结算截至 week 的每一个入职周年：发放上一年的薪水并按年资晋升。
+// 已经发过的年份不会重复发放，跳过的周年会一并补上
+func payCareer(state *model.GameState, week model.GameWeek) {
+	career := state.Career
+	if career == nil || career.Left != nil {
+		return
+	}
+	entry, ok := LookupCareer(career.Name)
+	if !ok {
+		return
+	}
+	elapsed := (week.Index() - career.Since.Index()) / (12 * model.WeeksPerMonth)
+	for years := career.PaidYears + 1; years <= elapsed; years++ {
+		rank := entry.Ranks[rankAt(entry, years-1)]
+		// 收入不会超出余额，Charge 不会拒绝
+		_ = Charge(state, model.Galleons(rank.Salary), fmt.Sprintf("%s第 %d 年薪水", career.Employer, years))
+		career.PaidYears = years
+		if next := entry.Ranks[rankAt(entry, years)]; next.Title != career.Rank {
+			career.Rank = next.Title
+			addMilestone(state, fmt.Sprintf("在%s任职满 %d 年，晋升为%s", career.Employer, years, next.Title))
+		}
+	}
+}
+
+// careerSummary 毕业之后每回合交给 GM 的职业、目标与最近的里程碑
+func careerSummary(state *model.GameState) *model.CareerSummary {
+	if !graduated(state) && state.Career == nil && len(state.Goals) == 0 {
+		return nil
+	}
+	summary := &model.CareerSummary{}
+	for _, goal := range state.Goals {
+		if goal.Done == nil {
+			summary.Goals = append(summary.Goals, goal)
+		}
+	}
+	career := state.Career
+	if career == nil || career.Left != nil {
+		if graduated(state) {
+			for _, entry := range careerCatalog {
+				if len(careerGaps(state, entry)) == 0 {
+					summary.Eligible = append(summary.Eligible, entry.Name)
+				}
+			}
+		}
+		return summary
+	}
+	record := *career
+	record.Milestones = nil
+	summary.Career = &record
+	summary.Milestones = career.Milestones[max(len(career.Milestones)-summaryMilestone, 0):]
+	if entry, ok := LookupCareer(career.Name); ok {
+		years := career.PaidYears
+		index := rankAt(entry, years)
+		summary.Salary = entry.Ranks[index].Salary
+		if index+1 < len(entry.Ranks) {
+			summary.NextRank = entry.Ranks[index+1].Title
+			summary.YearsTo = entry.Ranks[index+1].Years - years
+		}
+	}
+	return summary
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"encoding/json"
+	"log"
+)
+
+// mustLoadCatalog 解析 config 中内嵌的数据表，格式错误属于发布问题，直接终止启动
+func mustLoadCatalog[T any](name string, data []byte) T {
+	var catalog T
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		log.Fatalf("加载%s失败: %v", name, err)
+	}
+	return catalog
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+// 检定结果
+const (
+	OutcomeSuccess  = "success"
+	OutcomeNearMiss = "near_miss" // 惜败：差距在 5 以内
+	OutcomeFailure  = "failure"
+)
+
+// 熟练度加成: LV1=+3 / LV2=+6 / LV3=+10 / LV4=+15
+var spellLevelBonus = []float64{0, 3, 6, 10, 15}
+
+// 各年级学识上限，下标为年级
+var knowledgeYearCap = []int{30, 30, 45, 65, 105, 105, 145, 145}
+
+// 每年自然成长后的魔力上限封顶，下标为升入的年级，按魔力档位表：一至三年级 25，四至七年级 55，毕业 70
+var mpYearCap = []int{25, 25, 25, 25, 55, 55, 55, 55, 70}
+
+const schoolMaxMP = 70 // 在校期间魔力上限最高为 70，特训也不能突破
+
+const maxCheckLog = 100
+
+// rollDie 用角色专属种子掷一次骰子，同一存档同一游标总是得到相同点数
+func rollDie(state *model.GameState, sides int) int {
+	if state.RNGSeed == 0 {
+		state.RNGSeed = rand.Uint64() | 1
+	}
+	r := rand.New(rand.NewPCG(state.RNGSeed, state.RNGCursor))
+	state.RNGCursor++
+	return r.IntN(sides) + 1
+}
+
+// SchoolYear 角色当前所在年级，9 月开学升入下一年级
+func SchoolYear(status model.CharacterStatus) int {
+	year := status.CurrentYear - 1991
+	if status.CurrentMonth >= 9 {
+		year++
+	}
+	return min(max(year, 1), 7)
+}
+
+// CastCheck 施法检定: (魔力×0.4) + (心智×0.4) + 熟练度加成 + (学识×0.2) + D20
+func CastCheck(state *model.GameState, spell string, dc float64) model.CheckResult {
+	status := state.Status
+	modifier := float64(status.MP)*0.4 + float64(status.Mental)*0.4 + float64(status.Knowledge)*0.2 + levelBonus(state.Spells[spell].Level)
+	return settle(state, model.CheckResult{Kind: "施法:" + spell, Attribute: "mp/mental/knowledge", Dice: "D20", Modifier: modifier, DC: dc}, 20)
+}
+
+// DefenseCheck 闪避/防御检定: (体能×0.6) + (心智×0.2) + 预判加成 + D20
+func DefenseCheck(state *model.GameState, bonus float64) model.CheckResult {
+	status := state.Status
+	modifier := float64(status.Athletics)*0.6 + float64(status.Mental)*0.2 + bonus
+	return settle(state, model.CheckResult{Kind: "闪避", Attribute: "athletics/mental", Dice: "D20", Modifier: modifier}, 20)
+}
+
+// AttributeCheck 单属性检定: 属性×weight + D20，超过 dc 即成功
+func AttributeCheck(state *model.GameState, kind, attribute string, weight, dc float64) model.CheckResult {
+	modifier := float64(attributeValue(state.Status, attribute)) * weight
+	return settle(state, model.CheckResult{Kind: kind, Attribute: attribute, Dice: "D20", Modifier: modifier, DC: dc}, 20)
+}
+
+// PercentCheck 纯 D100 检定，结果需大于等于 dc
+func PercentCheck(state *model.GameState, kind string, dc float64) model.CheckResult {
+	return settle(state, model.CheckResult{Kind: kind, Dice: "D100", DC: dc}, 100)
+}
+
+// ThresholdCheck 不掷骰的门槛判定，如 精神力 > 75
+func ThresholdCheck(state *model.GameState, kind, attribute string, threshold float64) model.CheckResult {
+	value := float64(attributeValue(state.Status, attribute))
+	return ruleCheck(state, kind, attribute, value, threshold, value > threshold)
+}
+
+// ruleCheck 记录一次由规则直接决定、不需要掷骰的判定
+func ruleCheck(state *model.GameState, kind, attribute string, total, dc float64, ok bool) model.CheckResult {
+	check := model.CheckResult{Kind: kind, Attribute: attribute, Total: total, DC: dc, Outcome: OutcomeFailure}
+	if ok {
+		check.Outcome = OutcomeSuccess
+	}
+	return stamp(state, check)
+}
+
+func settle(state *model.GameState, check model.CheckResult, sides int) model.CheckResult {
+	check.Roll = rollDie(state, sides)
+	check.Total = float64(check.Roll) + check.Modifier
+	switch {
+	case check.Total >= check.DC:
+		check.Outcome = OutcomeSuccess
+	case check.Total >= check.DC-5:
+		check.Outcome = OutcomeNearMiss
+	default:
+		check.Outcome = OutcomeFailure
+	}
+	return stamp(state, check)
+}
+
+func stamp(state *model.GameState, check model.CheckResult) model.CheckResult {
+	check.Turn = state.Turn + 1
+	check.Week = model.WeekOf(state.Status)
+	return check
+}
+
+func levelBonus(level float64) float64 {
+	index := int(level)
+	if index < 0 {
+		return 0
+	}
+	if index >= len(spellLevelBonus) {
+		index = len(spellLevelBonus) - 1
+	}
+	return spellLevelBonus[index]
+}
+
+func attributeValue(status model.CharacterStatus, attribute string) int {
+	switch attribute {
+	case "knowledge":
+		return status.Knowledge
+	case "athletics":
+		return status.Athletics
+	case "charm":
+		return status.Charm
+	case "morality":
+		return status.Morality
+	case "mental":
+		return status.Mental
+	case "mp":
+		return status.MP
+	case "hp":
+		return status.HP
+	}
+	return 0
+}
+
+// RollTurnChecks 根据玩家本回合的行动掷出需要的检定，结果交给 GM 描写；「不去图书馆」这类否定说法不掷骰
+func RollTurnChecks(state *model.GameState, input string) []model.CheckResult {
+	checks := []model.CheckResult{}
+	status := state.Status
+	year := SchoolYear(status)
+
+	if affirmed(input, "死磕研究") {
+		check := PercentCheck(state, "死磕研究", 81)
+		if check.Outcome == OutcomeSuccess {
+			check.Effect = "学识 +1"
+		}
+		checks = append(checks, check)
+	} else if affirmed(input, "图书馆", "研读") {
+		checks = append(checks, studyCheck(state, year))
+	}
+	if affirmed(input, "上课", "全勤") && !affirmed(input, "逃课") {
+		// 学识未达年级上限时上课必定 +1
+		yearCap := knowledgeYearCap[year]
+		check := ruleCheck(state, "课堂学识", "knowledge", float64(status.Knowledge), float64(yearCap), status.Knowledge < yearCap)
+		check.Effect = "学识 +1"
+		if check.Outcome == OutcomeFailure {
+			check.Effect = "已达年级上限，改为学院分/教授好感/咒语熟练度三选一"
+		}
+		checks = append(checks, check)
+	}
+	if affirmed(input, "冥想", "大脑封闭术") {
+		check := PercentCheck(state, "冥想", 60)
+		if check.Outcome == OutcomeSuccess {
+			check.Effect = "心智 +1"
+		}
+		checks = append(checks, check)
+	}
+	if affirmed(input, "魔力特训") {
+		check := PercentCheck(state, "魔力特训", 81)
+		if check.Outcome == OutcomeSuccess && status.MaxMP < schoolMaxMP {
+			check.Effect = "魔力上限 +1"
+		}
+		checks = append(checks, check)
+	}
+	for _, name := range slices.Sorted(maps.Keys(state.Spells)) {
+		if affirmed(input, name) {
+			check := CastCheck(state, name, spellDC(name))
+			check.Effect = practiceEffect(check.Outcome)
+			checks = append(checks, check)
+		}
+	}
+	if affirmed(input, "说服", "搭讪", "求助", "社交") {
+		checks = append(checks, AttributeCheck(state, "社交", "charm", 0.4, 30))
+	}
+	if affirmed(input, "飞行", "魁地奇", "体能训练") {
+		checks = append(checks, AttributeCheck(state, "体能", "athletics", 0.6, 35))
+	}
+	if affirmed(input, "黑魔法", "不可饶恕") {
+		// D100 不低于品性才能接触到黑魔法，品性越低越容易
+		check := PercentCheck(state, "黑魔法门路", float64(status.Morality))
+		check.Attribute = "morality"
+		checks = append(checks, check)
+	}
+
+	state.CheckLog = append(state.CheckLog, checks...)
+	if len(state.CheckLog) > maxCheckLog {
+		state.CheckLog = state.CheckLog[len(state.CheckLog)-maxCheckLog:]
+	}
+	return checks
+}
+
+// studyCheck 图书馆研读：低于年级上限必定 +1，低于下一年级上限 D100>50 才 +1
+func studyCheck(state *model.GameState, year int) model.CheckResult {
+	knowledge := state.Status.Knowledge
+	if knowledge < knowledgeYearCap[year] {
+		check := ruleCheck(state, "图书馆研读", "knowledge", float64(knowledge), float64(knowledgeYearCap[year]), true)
+		check.Effect = "学识 +1"
+		return check
+	}
+	nextCap := knowledgeYearCap[min(year+1, len(knowledgeYearCap)-1)]
+	if knowledge < nextCap {
+		check := PercentCheck(state, "图书馆研读", 51)
+		if check.Outcome == OutcomeSuccess {
+			check.Effect = "学识 +1"
+		}
+		return check
+	}
+	check := ruleCheck(state, "图书馆研读", "knowledge", float64(knowledge), float64(nextCap), false)
+	check.Effect = fmt.Sprintf("学识已达 %d，需写信请教教授、进入禁书区或死磕研究才能突破", nextCap)
+	return check
+}
+
+func practiceEffect(outcome string) string {
+	switch outcome {
+	case OutcomeSuccess:
+		return "熟练度稳定提升"
+	case OutcomeNearMiss:
+		return "施法失败（冒烟/火花），熟练度微量提升"
+	}
+	return "毫无头绪或炸膛，熟练度不变"
+}
+
+func containsAny(text string, keywords ...string) bool {
+	for _, keyword := range keywords {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// 出现在关键词之前、同一分句里时表示玩家不做这件事，如「拒绝和马尔福决斗」「不去告发」
+	negationWords = []string{"不", "没", "别", "拒绝", "避免", "放弃", "反对", "谴责", "阻止", "劝阻", "躲开"}
+	// 带「不」字却是肯定语气的说法
+	affirmativePhrases = []string{"不得不", "不管", "不顾", "毫不", "不甘"}
+)
+
+// affirmedClauses 指令中提到关键词、且关键词前没有否定词的分句
+func affirmedClauses(text string, keywords ...string) []string {
+	var clauses []string
+	for _, clause := range strings.FieldsFunc(text, isClauseBreak) {
+		for _, keyword := range keywords {
+			index := strings.Index(clause, keyword)
+			if index == -1 {
+				continue
+			}
+			before := clause[:index]
+			for _, phrase := range affirmativePhrases {
+				before = strings.ReplaceAll(before, phrase, "")
+			}
+			if !containsAny(before, negationWords...) {
+				clauses = append(clauses, clause)
+				break
+			}
+		}
+	}
+	return clauses
+}
+
+// affirmed 指令中有没有被否定地提到任一关键词
+func affirmed(text string, keywords ...string) bool {
+	return len(affirmedClauses(text, keywords...)) > 0
+}
+
+func isClauseBreak(r rune) bool {
+	return strings.ContainsRune("，。！？；：,.!?;:\n", r)
+}
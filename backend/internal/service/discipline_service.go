@@ -0,0 +1,184 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+// 违纪严重程度
+const (
+	SeverityMinor    = "minor"
+	SeverityModerate = "moderate"
+	SeveritySevere   = "severe"
+)
+
+// 禁闭状态
+const (
+	DetentionPending = "pending"
+	DetentionServed  = "served"
+	DetentionMissed  = "missed"
+)
+
+const (
+	defaultSupervisor = "阿格斯·费尔奇"
+	skipDetentionRule = "逃避禁闭"
+	maxViolations     = 100
+)
+
+// 各严重程度默认扣除的学院分，一般及以上还要关禁闭
+var severityPoints = map[string]int{SeverityMinor: 10, SeverityModerate: 20, SeveritySevere: 50}
+
+// 校规表：按关键词判断违纪的默认严重程度，都不匹配的(如顶撞教授)算轻微
+var ruleSeverities = []struct {
+	Keywords []string
+	Severity string
+}{
+	{[]string{"禁林", "禁区", "密室", "霍格莫德", "离校", "黑魔法", "不可饶恕"}, SeveritySevere},
+	{[]string{"宵禁", "夜游", "禁书区", "公共休息室", "办公室", "休息室", "盥洗室", "决斗", "斗殴", skipDetentionRule}, SeverityModerate},
+}
+
+type DisciplineService struct{}
+
+// DisciplineRecord 违纪记录、未服完的禁闭与尚未被发现的违规
+type DisciplineRecord struct {
+	Violations []model.Violation `json:"violations"`
+	Detentions []model.Detention `json:"detentions"`
+	RuleBreaks []model.RuleBreak `json:"rule_breaks"`
+}
+
+func (s *DisciplineService) Record(state model.GameState) DisciplineRecord {
+	record := DisciplineRecord{
+		Violations: slices.Clone(state.Violations),
+		Detentions: outstandingDetentions(&state),
+		RuleBreaks: slices.Clone(state.RuleBreaks),
+	}
+	if record.Violations == nil {
+		record.Violations = []model.Violation{}
+	}
+	if record.RuleBreaks == nil {
+		record.RuleBreaks = []model.RuleBreak{}
+	}
+	return record
+}
+
+func ruleSeverity(rule string) string {
+	for _, entry := range ruleSeverities {
+		if containsAny(rule, entry.Keywords...) {
+			return entry.Severity
+		}
+	}
+	return SeverityMinor
+}
+
+// applyViolations 记录被抓到的违纪：扣学院分，一般及以上或写明禁闭内容时安排禁闭
+func applyViolations(state *model.GameState, changes []model.ViolationChange) []string {
+	var warnings []string
+	week := model.WeekOf(state.Status)
+	house := NormalizeHouse(state.Profile.House)
+	for _, change := range changes {
+		if change.Rule == "" {
+			warnings = append(warnings, "违纪记录缺少违反的校规")
+			continue
+		}
+		severity := change.Severity
+		if _, ok := severityPoints[severity]; !ok {
+			severity = ruleSeverity(change.Rule)
+		}
+		points := change.Points
+		if points <= 0 {
+			points = severityPoints[severity]
+		}
+		reason := change.Rule
+		if change.CaughtBy != "" {
+			reason = fmt.Sprintf("%s(被%s抓到)", change.Rule, change.CaughtBy)
+		}
+		if house != "" {
+			recordHousePoints(state, week, house, -points, reason, false)
+		}
+		state.Violations = append(state.Violations, model.Violation{
+			Turn:     state.Turn + 1,
+			Week:     week,
+			Rule:     change.Rule,
+			Severity: severity,
+			CaughtBy: change.CaughtBy,
+			Location: state.Status.Location,
+			Points:   points,
+		})
+		if change.Detention != "" || severity != SeverityMinor {
+			if warning := scheduleDetention(state, change, reason); warning != "" {
+				warnings = append(warnings, warning)
+			}
+		}
+	}
+	if len(state.Violations) > maxViolations {
+		state.Violations = state.Violations[len(state.Violations)-maxViolations:]
+	}
+	return warnings
+}
+
+// scheduleDetention 禁闭默认安排在下一周，由抓到玩家的教职工监督，其他人抓到的交给费尔奇
+func scheduleDetention(state *model.GameState, change model.ViolationChange, reason string) string {
+	warning := ""
+	now := model.WeekOf(state.Status)
+	week := model.WeekFromIndex(now.Index() + 1)
+	if change.DetentionWeek != "" {
+		if parsed, err := model.ParseWeek(change.DetentionWeek, now); err != nil {
+			warning = fmt.Sprintf("禁闭日期%s，改在 %s", err, week)
+		} else {
+			week = parsed
+		}
+	}
+	supervisor := defaultSupervisor
+	if entry, ok := LookupNPC(change.CaughtBy); ok && entry.Role != "学生" && slices.Contains(introducedRoles, entry.Role) {
+		supervisor = entry.Name
+	}
+	state.Detentions = append(state.Detentions, model.Detention{
+		Week:       week,
+		Supervisor: supervisor,
+		Task:       change.Detention,
+		Reason:     reason,
+		Status:     DetentionPending,
+	})
+	if len(state.Detentions) > maxViolations {
+		state.Detentions = state.Detentions[len(state.Detentions)-maxViolations:]
+	}
+	return warning
+}
+
+// skipDetention 玩家本周逃避禁闭时记为缺席，并按新的违纪再罚一次
+func skipDetention(state *model.GameState, input string) {
+	if !containsAny(input, "逃避禁闭", "不去禁闭", "翘掉禁闭", "逃掉禁闭") {
+		return
+	}
+	now := model.WeekOf(state.Status)
+	for i := range state.Detentions {
+		detention := &state.Detentions[i]
+		if detention.Status != DetentionPending || detention.Week != now {
+			continue
+		}
+		detention.Status = DetentionMissed
+		applyViolations(state, []model.ViolationChange{{Rule: skipDetentionRule, CaughtBy: detention.Supervisor, Detention: detention.Task}})
+		return
+	}
+}
+
+// settleDetentions 禁闭周过去后视为已服完
+func settleDetentions(state *model.GameState, week model.GameWeek) {
+	for i := range state.Detentions {
+		if detention := &state.Detentions[i]; detention.Status == DetentionPending && detention.Week.Index() < week.Index() {
+			detention.Status = DetentionServed
+		}
+	}
+}
+
+func outstandingDetentions(state *model.GameState) []model.Detention {
+	detentions := []model.Detention{}
+	for _, detention := range state.Detentions {
+		if detention.Status == DetentionPending {
+			detentions = append(detentions, detention)
+		}
+	}
+	return detentions
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/config"
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+// 决斗咒语的效果
+const (
+	duelEffectDamage = "damage"
+	duelEffectDisarm = "disarm" // 缴械，压制性命中即结束决斗
+	duelEffectStun   = "stun"   // 昏迷/石化/捆绑，压制性命中即结束决斗
+)
+
+const (
+	maxDuelRounds     = 6
+	decisiveMargin    = 5 // 攻击超出防御 5 点以上才算压制性命中
+	shieldSpell       = "盔甲护身"
+	shieldBonus       = 5
+	defaultDuelMPCost = 5
+	unforgivableTier  = 6
+)
+
+// DuelWinner 取值
+const (
+	DuelPlayer   = "player"
+	DuelOpponent = "opponent"
+	DuelDraw     = "draw"
+)
+
+type duelSpell struct {
+	Damage int
+	Effect string
+	Kind   string // dark 被高心智克制，physical 被高体能克制
+}
+
+// 能用于决斗的咒语，其余咒语在决斗中不会被选用
+var duelSpells = map[string]duelSpell{
+	"退敌三尺":  {8, duelEffectDamage, "physical"},
+	"锁腿咒":   {6, duelEffectDamage, ""},
+	"塔朗泰拉舞": {6, duelEffectDamage, ""},
+	"火焰熊熊":  {12, duelEffectDamage, "physical"},
+	"冰冻咒":   {10, duelEffectDamage, "physical"},
+	"除你武器":  {5, duelEffectDisarm, ""},
+	"统统石化":  {5, duelEffectStun, ""},
+	"速速禁锢":  {8, duelEffectStun, "physical"},
+	"昏昏倒地":  {10, duelEffectStun, ""},
+	"障碍重重":  {12, duelEffectDamage, "physical"},
+	"四分五裂":  {20, duelEffectDamage, "physical"},
+	"粉身碎骨":  {25, duelEffectDamage, "physical"},
+	"神锋无影":  {30, duelEffectDamage, "dark"},
+	"厉火":    {35, duelEffectDamage, "physical"},
+	"钻心剜骨":  {30, duelEffectDamage, "dark"},
+	"阿瓦达索命": {100, duelEffectDamage, "dark"},
+}
+
+var duelEffectText = map[string]string{duelEffectDisarm: "缴械", duelEffectStun: "制服"}
+
+var duelistCatalog = mustLoadCatalog[[]model.DuelistEntry]("决斗对手", config.DuelistCatalog)
+
+type duelist struct {
+	name      string
+	hp        int
+	mp        int
+	athletics int
+	mental    int
+	knowledge int
+	spells    map[string]float64
+	startHP   int
+	beaten    bool
+}
+
+type DuelService struct{}
+
+// Duel 玩家与 NPC 逐回合对决，玩家每回合先手；结算后的 HP/MP 直接写回存档
+func (s *DuelService) Duel(state *model.GameState, opponent model.DuelistEntry, input string) *model.DuelResult {
+	result := simulateDuel(state, opponent, input)
+	applyDuel(state, result)
+	return result
+}
+
+// applyDuel 把决斗结束时的 HP/MP 写回存档，回合内在模型回复成功后的 Resolve 中调用
+func applyDuel(state *model.GameState, result *model.DuelResult) {
+	if result != nil {
+		state.Status.HP, state.Status.MP = result.PlayerHP, result.PlayerMP
+	}
+}
+
+// simulateDuel 只推演决斗过程，不改动角色状态
+func simulateDuel(state *model.GameState, opponent model.DuelistEntry, input string) *model.DuelResult {
+	player := &duelist{
+		name:      playerName(state),
+		hp:        state.Status.HP,
+		mp:        state.Status.MP,
+		athletics: state.Status.Athletics,
+		mental:    state.Status.Mental,
+		knowledge: state.Status.Knowledge,
+		spells:    map[string]float64{},
+		startHP:   max(state.Status.HP, 1),
+	}
+	for name, spell := range state.Spells {
+		entry, ok := LookupSpell(name)
+		// 不可饶恕咒只有玩家明确下令时才会使用
+		if ok && (entry.Tier < unforgivableTier || strings.Contains(input, entry.Name)) {
+			player.spells[entry.Name] = spell.Level
+		}
+	}
+	npc := &duelist{
+		name:      opponent.Name,
+		hp:        opponent.HP,
+		mp:        opponent.MP,
+		athletics: opponent.Athletics,
+		mental:    opponent.Mental,
+		knowledge: opponent.Knowledge,
+		spells:    opponent.Spells,
+		startHP:   max(opponent.HP, 1),
+	}
+
+	result := &model.DuelResult{Opponent: opponent.Name, Rounds: []model.DuelRound{}}
+	for round := 1; round <= maxDuelRounds && !duelOver(player, npc); round++ {
+		for _, pair := range [][2]*duelist{{player, npc}, {npc, player}} {
+			if duelOver(player, npc) {
+				break
+			}
+			result.Rounds = append(result.Rounds, duelExchange(state, round, pair[0], pair[1]))
+		}
+	}
+
+	switch {
+	case npc.beaten && !player.beaten:
+		result.Winner = DuelPlayer
+	case player.beaten && !npc.beaten:
+		result.Winner = DuelOpponent
+	case player.hp*npc.startHP > npc.hp*player.startHP:
+		// 回合用尽时按剩余生命比例判定
+		result.Winner = DuelPlayer
+	case player.hp*npc.startHP < npc.hp*player.startHP:
+		result.Winner = DuelOpponent
+	default:
+		result.Winner = DuelDraw
+	}
+	result.PlayerHP, result.PlayerMP = player.hp, player.mp
+	result.OpponentHP, result.OpponentMP = npc.hp, npc.mp
+	return result
+}
+
+func duelOver(a, b *duelist) bool {
+	return a.beaten || b.beaten
+}
+
+// duelExchange 一次出手：施法效能 对 闪避/防御效能，公式同施法检定
+func duelExchange(state *model.GameState, round int, attacker, defender *duelist) model.DuelRound {
+	entry := model.DuelRound{Round: round, Actor: attacker.name}
+	name, ok := pickDuelSpell(attacker)
+	if !ok {
+		entry.Effect = "魔力耗尽，无法施法"
+		return entry
+	}
+	spell, level := duelSpells[name], attacker.spells[name]
+	entry.Spell = name
+	entry.Attack = float64(attacker.mp)*0.4 + float64(attacker.mental)*0.4 + levelBonus(level) + float64(attacker.knowledge)*0.2 + float64(rollDie(state, 20))
+	attacker.mp -= duelMPCost(name)
+
+	entry.Defense = float64(defender.athletics)*0.6 + float64(defender.mental)*0.2 + float64(rollDie(state, 20))
+	switch spell.Kind {
+	case "dark":
+		entry.Defense += float64(defender.mental) * 0.2
+	case "physical":
+		entry.Defense += float64(defender.athletics) * 0.2
+	}
+	shielded := false
+	if shieldLevel := defender.spells[shieldSpell]; shieldLevel >= 1 && defender.mp >= duelMPCost(shieldSpell) {
+		entry.Defense += levelBonus(shieldLevel) + shieldBonus
+		defender.mp -= duelMPCost(shieldSpell)
+		shielded = true
+	}
+
+	entry.Hit = entry.Attack > entry.Defense
+	switch {
+	case !entry.Hit && shielded:
+		entry.Effect = defender.name + "用盔甲护身挡下了咒语"
+	case !entry.Hit:
+		entry.Effect = defender.name + "闪开了"
+	case spell.Effect != duelEffectDamage && entry.Attack-entry.Defense >= decisiveMargin:
+		defender.beaten = true
+		entry.Effect = defender.name + "被" + duelEffectText[spell.Effect]
+	default:
+		damage := spell.Damage + int(levelBonus(level))
+		defender.hp = max(defender.hp-damage, 0)
+		entry.Effect = fmt.Sprintf("%s受到 %d 点伤害，剩余 HP %d", defender.name, damage, defender.hp)
+		if defender.hp == 0 {
+			defender.beaten = true
+			entry.Effect += "，倒地不起"
+		}
+	}
+	return entry
+}
+
+// pickDuelSpell 选出魔力足够、威力最大的决斗咒语，能一击制胜的咒语优先
+func pickDuelSpell(caster *duelist) (string, bool) {
+	best, bestScore := "", -1.0
+	for _, name := range slices.Sorted(maps.Keys(caster.spells)) {
+		spell, ok := duelSpells[name]
+		level := caster.spells[name]
+		if !ok || level < 1 || caster.mp < duelMPCost(name) {
+			continue
+		}
+		score := float64(spell.Damage) + levelBonus(level)
+		if spell.Effect != duelEffectDamage {
+			score += 15
+		}
+		if score > bestScore {
+			best, bestScore = name, score
+		}
+	}
+	return best, best != ""
+}
+
+func duelMPCost(name string) int {
+	if entry, ok := LookupSpell(name); ok && entry.MPCost > 0 {
+		return entry.MPCost
+	}
+	return defaultDuelMPCost
+}
+
+// LookupDuelist 按名字或别名查找对应年级的 NPC 决斗属性
+func LookupDuelist(name string, year int) (model.DuelistEntry, bool) {
+	for _, entry := range duelistCatalog {
+		if year < entry.MinYear || year > entry.MaxYear {
+			continue
+		}
+		if entry.Name == name || slices.Contains(entry.Aliases, name) {
+			return entry, true
+		}
+	}
+	return model.DuelistEntry{}, false
+}
+
+// duelOpponent 玩家指令的同一分句里明确要决斗并点名了图鉴中的对手时返回该对手，
+// 「拒绝和马尔福决斗」这类否定说法不会触发
+func duelOpponent(input string, year int) (model.DuelistEntry, bool) {
+	for _, clause := range affirmedClauses(input, "决斗", "对决", "交手", "迎战") {
+		for _, entry := range duelistCatalog {
+			if year < entry.MinYear || year > entry.MaxYear {
+				continue
+			}
+			for _, name := range append([]string{entry.Name}, entry.Aliases...) {
+				if strings.Contains(clause, name) {
+					return entry, true
+				}
+			}
+		}
+	}
+	return model.DuelistEntry{}, false
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+const (
+	weaknessEffect = "虚弱"
+	weaknessHP     = 30 // 生命低于该值陷入虚弱
+	maxEffects     = 20
+)
+
+var weaknessModifiers = map[string]int{"athletics": -10, "mental": -5}
+
+// applyEffectChanges 按 state_update 添加或移除持续状态，同名状态会被新的覆盖
+func applyEffectChanges(state *model.GameState, changes []model.EffectChange) []string {
+	var warnings []string
+	for _, change := range changes {
+		if change.Name == "" {
+			continue
+		}
+		if change.Remove {
+			if !removeEffect(state, change.Name) {
+				warnings = append(warnings, fmt.Sprintf("角色身上没有「%s」状态", change.Name))
+			}
+			continue
+		}
+		if len(state.Effects) >= maxEffects {
+			warnings = append(warnings, fmt.Sprintf("持续状态已达 %d 个上限，忽略「%s」", maxEffects, change.Name))
+			continue
+		}
+		addEffect(state, model.StatusEffect{
+			Name:           change.Name,
+			Source:         change.Source,
+			Modifiers:      change.Modifiers,
+			Tick:           change.Tick,
+			RemainingWeeks: max(change.Weeks, 0),
+			Cure:           change.Cure,
+		})
+	}
+	return warnings
+}
+
+func addEffect(state *model.GameState, effect model.StatusEffect) {
+	removeEffect(state, effect.Name)
+	effect.Since = model.WeekOf(state.Status)
+	effect.Applied = shiftStatus(&state.Status, effect.Modifiers, 1)
+	state.Effects = append(state.Effects, effect)
+}
+
+// removeEffect 移除状态并还原它加在属性上的数值
+func removeEffect(state *model.GameState, name string) bool {
+	index := slices.IndexFunc(state.Effects, func(effect model.StatusEffect) bool { return effect.Name == name })
+	if index == -1 {
+		return false
+	}
+	shiftStatus(&state.Status, state.Effects[index].Applied, -1)
+	state.Effects = slices.Delete(state.Effects, index, index+1)
+	return true
+}
+
+// shiftStatus 把数值按 sign 加到属性上，返回受上下限影响后实际变化的数值
+func shiftStatus(status *model.CharacterStatus, deltas map[string]int, sign int) map[string]int {
+	applied := make(map[string]int, len(deltas))
+	for _, key := range slices.Sorted(maps.Keys(deltas)) {
+		before := attributeValue(*status, key)
+		applyItemEffects(status, map[string]int{key: deltas[key] * sign}, 1)
+		if delta := attributeValue(*status, key) - before; delta != 0 {
+			applied[key] = delta
+		}
+	}
+	return applied
+}
+
+// cureEffects 使用了治愈物品或到了治愈地点的状态立即解除
+func cureEffects(state *model.GameState, usedItems []string) {
+	for _, effect := range slices.Clone(state.Effects) {
+		if effect.Cure == "" {
+			continue
+		}
+		cured := strings.Contains(state.Status.Location, effect.Cure)
+		for _, item := range usedItems {
+			cured = cured || strings.Contains(item, effect.Cure)
+		}
+		if cured {
+			removeEffect(state, effect.Name)
+		}
+	}
+}
+
+// tickEffects 日历每推进一周结算一次持续伤害或恢复，到期的状态自动解除
+func tickEffects(state *model.GameState) {
+	for _, effect := range slices.Clone(state.Effects) {
+		applyItemEffects(&state.Status, effect.Tick, 1)
+		if effect.RemainingWeeks == 0 {
+			continue
+		}
+		if effect.RemainingWeeks == 1 {
+			removeEffect(state, effect.Name)
+			continue
+		}
+		index := slices.IndexFunc(state.Effects, func(e model.StatusEffect) bool { return e.Name == effect.Name })
+		state.Effects[index].RemainingWeeks--
+	}
+}
+
+// syncWeakness 生命过低时陷入虚弱，恢复后自动解除
+func syncWeakness(state *model.GameState) {
+	weak := slices.ContainsFunc(state.Effects, func(effect model.StatusEffect) bool { return effect.Name == weaknessEffect })
+	switch {
+	case state.Status.HP < weaknessHP && !weak:
+		addEffect(state, model.StatusEffect{
+			Name:      weaknessEffect,
+			Source:    "生命过低",
+			Modifiers: weaknessModifiers,
+			Cure:      fmt.Sprintf("生命恢复到 %d 以上", weaknessHP),
+		})
+	case state.Status.HP >= weaknessHP && weak:
+		removeEffect(state, weaknessEffect)
+	}
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/config"
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+const (
+	ExamMonth = 6 // 6 月第 1 周：期末考试 / O.W.L.s / N.E.W.T.s
+	ExamWeek  = 1
+
+	OWLYear  = 5
+	NEWTYear = 7
+
+	defaultAttendance = 0.75 // 没有任何出勤记录时按普通学生计
+	boardExamPenalty  = 5    // 魔法部考试委员会阅卷更严格
+)
+
+var subjectCatalog = mustLoadCatalog[[]model.SubjectEntry]("课程目录", config.SubjectCatalog)
+
+// 分数线从高到低：优秀 O / 良好 E / 及格 A / 差 P / 很差 D / 巨怪 T
+var gradeThresholds = []struct {
+	Min   float64
+	Grade string
+}{
+	{85, "O"}, {70, "E"}, {55, "A"}, {40, "P"}, {25, "D"}, {0, "T"},
+}
+
+// ExamSubjects 角色本学年需要参加考试的科目
+func ExamSubjects(state model.GameState) []model.SubjectEntry {
+	subjects := []model.SubjectEntry{}
+	for _, subject := range EnrolledSubjects(state) {
+		if subject.Exam {
+			subjects = append(subjects, subject)
+		}
+	}
+	return subjects
+}
+
+// examName 五年级考 O.W.L.，七年级考 N.E.W.T.，其余年级为普通期末考试
+func examName(year int) string {
+	switch year {
+	case OWLYear:
+		return "O.W.L."
+	case NEWTYear:
+		return "N.E.W.T."
+	}
+	return "期末考试"
+}
+
+// recordAttendance 按玩家本周的课堂选择记录出勤：全勤 1 次，摸鱼半次，逃课 0 次
+func recordAttendance(state *model.GameState, input string) {
+	week := model.WeekOf(state.Status)
+	if !isTermWeek(week) || !containsAny(input, "上课", "全勤", "逃课", "摸鱼") {
+		return
+	}
+	record := &state.Attendance
+	if record.SchoolYear != schoolYearLabel(week) {
+		*record = model.AttendanceRecord{SchoolYear: schoolYearLabel(week)}
+	}
+	if record.Sessions > 0 && record.LastWeek == week {
+		return
+	}
+	switch {
+	case strings.Contains(input, "逃课"):
+	case strings.Contains(input, "摸鱼"):
+		record.Attended += 0.5
+	default:
+		record.Attended++
+	}
+	record.Sessions++
+	record.LastWeek = week
+}
+
+// attendanceRate 本学年出勤率
+func attendanceRate(state *model.GameState, week model.GameWeek) float64 {
+	record := state.Attendance
+	if record.SchoolYear != schoolYearLabel(week) || record.Sessions == 0 {
+		return defaultAttendance
+	}
+	return record.Attended / float64(record.Sessions)
+}
+
+// subjectScore 单科得分：学识占 50 分，实践(咒语/技能熟练度)占 20 分，出勤占 20 分，临场发挥 D20-10
+func subjectScore(state *model.GameState, subject model.SubjectEntry, year int, attendance float64) float64 {
+	ratio := min(float64(state.Status.Knowledge)/float64(knowledgeYearCap[year]), 1.2)
+	score := 50*ratio + 20*practicalRatio(state, subject, ratio) + 20*attendance
+	score += float64(rollDie(state, 20) - 10)
+	if year == OWLYear || year == NEWTYear {
+		score -= boardExamPenalty
+	}
+	return math.Round(min(max(score, 0), 100))
+}
+
+// practicalRatio 取本科目最熟练的三项咒语/技能的平均熟练度，科目不足三项时按实际项数平均；
+// 纯理论科目且未练过对应技能时按学识折算
+func practicalRatio(state *model.GameState, subject model.SubjectEntry, knowledgeRatio float64) float64 {
+	names := slices.DeleteFunc(append([]string{subject.Skill}, subject.Spells...), func(name string) bool { return name == "" })
+	levels := []float64{}
+	for _, name := range names {
+		if spell, ok := state.Spells[name]; ok {
+			levels = append(levels, spell.Level)
+		}
+	}
+	if len(levels) == 0 && len(subject.Spells) == 0 {
+		return min(knowledgeRatio, 1)
+	}
+	slices.SortFunc(levels, func(a, b float64) int { return cmp.Compare(b, a) })
+	counted := min(len(names), 3)
+	total := 0.0
+	for _, level := range levels[:min(len(levels), counted)] {
+		total += min(level, MaxSpellLevel)
+	}
+	return total / float64(counted) / MaxSpellLevel
+}
+
+func gradeOf(score float64) string {
+	for _, threshold := range gradeThresholds {
+		if score >= threshold.Min {
+			return threshold.Grade
+		}
+	}
+	return "T"
+}
+
+// gradeExams 考试周为本学年的各科评分并存入成绩单，毕业之后不再考试
+func gradeExams(state *model.GameState, week model.GameWeek) {
+	label := schoolYearLabel(week)
+	if card := lastReportCard(state); graduated(state) || card != nil && card.SchoolYear == label {
+		return
+	}
+	year := schoolYearAt(week)
+	attendance := attendanceRate(state, week)
+	card := model.ReportCard{
+		SchoolYear: label,
+		Year:       year,
+		Exam:       examName(year),
+		Week:       week,
+		Attendance: attendance,
+		Grades:     []model.SubjectGrade{},
+	}
+	for _, subject := range enrolledSubjectsIn(*state, year) {
+		if !subject.Exam {
+			continue
+		}
+		score := subjectScore(state, subject, year, attendance)
+		grade := gradeOf(score)
+		card.Grades = append(card.Grades, model.SubjectGrade{
+			Subject: subject.Name,
+			Grade:   grade,
+			Score:   score,
+			Passed:  grade == "O" || grade == "E" || grade == "A",
+		})
+	}
+	state.ReportCards = append(state.ReportCards, card)
+}
+
+func lastReportCard(state *model.GameState) *model.ReportCard {
+	if len(state.ReportCards) == 0 {
+		return nil
+	}
+	return &state.ReportCards[len(state.ReportCards)-1]
+}
+
+// reportCardThisTerm 考试周到学年终宴之间返回本学年的成绩单，供 GM 描写考试与放榜
+func reportCardThisTerm(state *model.GameState) *model.ReportCard {
+	week := model.WeekOf(state.Status)
+	card := lastReportCard(state)
+	if week.Month != ExamMonth || week.Week < ExamWeek || week.Week > LeavingFeastWeek || card == nil {
+		return nil
+	}
+	if card.SchoolYear != schoolYearLabel(week) {
+		return nil
+	}
+	result := *card
+	return &result
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/config"
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+const (
+	maxReputation      = 100
+	maxReputationDelta = 20 // 单次声望变化上限
+	maxReputationLog   = 200
+)
+
+var factionCatalog = mustLoadCatalog[[]model.FactionEntry]("阵营", config.FactionCatalog)
+
+// 声望档位从高到低
+var standingTiers = []struct {
+	Min   int
+	Label string
+}{
+	{80, "核心"}, {50, "信任"}, {10, "友善"}, {-9, "中立"}, {-49, "冷淡"}, {-maxReputation, "敌对"},
+}
+
+type FactionService struct{}
+
+// Catalog 全部阵营及其专属内容
+func (s *FactionService) Catalog() []model.FactionEntry {
+	return factionCatalog
+}
+
+// Standings 角色在当前这一周已经存在的各个阵营中的地位
+func (s *FactionService) Standings(state model.GameState) []model.FactionStanding {
+	week := model.WeekOf(state.Status)
+	standings := []model.FactionStanding{}
+	for _, faction := range factionCatalog {
+		if !factionActive(faction, week) {
+			continue
+		}
+		score := state.Reputation[faction.Name]
+		standing := model.FactionStanding{Faction: faction.Name, Score: score, Standing: standingLabel(score)}
+		for _, gate := range faction.Gates {
+			if gateOpen(&state, gate, score) {
+				standing.Unlocked = append(standing.Unlocked, gate.Content)
+			} else {
+				standing.Locked = append(standing.Locked, gate.Content)
+			}
+		}
+		standings = append(standings, standing)
+	}
+	return standings
+}
+
+func standingLabel(score int) string {
+	for _, tier := range standingTiers {
+		if score >= tier.Min {
+			return tier.Label
+		}
+	}
+	return standingTiers[len(standingTiers)-1].Label
+}
+
+// factionActive 阵营在这一周是否已经成立，如凤凰社在 1995 年伏地魔归来后才重新集结
+func factionActive(faction model.FactionEntry, week model.GameWeek) bool {
+	return faction.From == nil || week.Index() >= faction.From.Index()
+}
+
+func gateOpen(state *model.GameState, gate model.FactionGate, score int) bool {
+	return score >= gate.Min && (gate.MaxMorality == 0 || state.Status.Morality <= gate.MaxMorality)
+}
+
+// LookupFaction 按名字或别名查找阵营
+func LookupFaction(name string) (model.FactionEntry, bool) {
+	name = strings.TrimSpace(name)
+	for _, faction := range factionCatalog {
+		if faction.Name == name || slices.Contains(faction.Aliases, name) {
+			return faction, true
+		}
+	}
+	return model.FactionEntry{}, false
+}
+
+// changeReputation 调整声望并记流水，尚未成立的阵营不接受变化
+func changeReputation(state *model.GameState, name string, delta int, reason string) string {
+	faction, ok := LookupFaction(name)
+	if !ok {
+		return fmt.Sprintf("没有名为「%s」的阵营", name)
+	}
+	week := model.WeekOf(state.Status)
+	if !factionActive(faction, week) {
+		return fmt.Sprintf("%s在 %s 之前尚未成立，声望变化无效", faction.Name, faction.From)
+	}
+	delta = min(max(delta, -maxReputationDelta), maxReputationDelta)
+	if state.Reputation == nil {
+		state.Reputation = make(map[string]int)
+	}
+	score := min(max(state.Reputation[faction.Name]+delta, -maxReputation), maxReputation)
+	if score == state.Reputation[faction.Name] {
+		return ""
+	}
+	state.Reputation[faction.Name] = score
+	state.ReputationLog = append(state.ReputationLog, model.ReputationEntry{
+		Turn:    state.Turn + 1,
+		Week:    week,
+		Faction: faction.Name,
+		Delta:   delta,
+		Score:   score,
+		Reason:  reason,
+	})
+	if len(state.ReputationLog) > maxReputationLog {
+		state.ReputationLog = state.ReputationLog[len(state.ReputationLog)-maxReputationLog:]
+	}
+	return ""
+}
+
+// applyReputation 记录 state_update 中的声望变化
+func applyReputation(state *model.GameState, changes []model.ReputationChange) []string {
+	var warnings []string
+	for _, change := range changes {
+		if change.Faction == "" || change.Delta == 0 {
+			continue
+		}
+		if warning := changeReputation(state, change.Faction, change.Delta, change.Reason); warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings
+}
+
+// reputationFromActions 玩家选中的行动选项里立场鲜明的行动直接影响相关阵营的声望，
+// 「不去告发」这类否定说法不算，自定义行动交给模型按剧情填写 reputation
+func reputationFromActions(state *model.GameState, chosen []model.ActionOption) {
+	week := model.WeekOf(state.Status)
+	for _, option := range chosen {
+		for _, faction := range factionCatalog {
+			if !factionActive(faction, week) {
+				continue
+			}
+			for _, action := range faction.Actions {
+				for _, keyword := range action.Keywords {
+					if len(affirmedClauses(option.Label, keyword)) > 0 {
+						changeReputation(state, faction.Name, action.Delta, "行动："+keyword)
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+// gatedContent 玩家想接触、但声望或品性还不够的阵营专属内容
+func gatedContent(state *model.GameState, input string) []string {
+	week := model.WeekOf(state.Status)
+	gated := []string{}
+	for _, faction := range factionCatalog {
+		score := state.Reputation[faction.Name]
+		for _, gate := range faction.Gates {
+			if !containsAny(input, gate.Keywords...) {
+				continue
+			}
+			switch {
+			case !factionActive(faction, week):
+				gated = append(gated, fmt.Sprintf("%s：%s此时尚未成立", gate.Content, faction.Name))
+			case score < gate.Min:
+				gated = append(gated, fmt.Sprintf("%s：需要%s声望 %d（当前 %d）", gate.Content, faction.Name, gate.Min, score))
+			case !gateOpen(state, gate, score):
+				gated = append(gated, fmt.Sprintf("%s：品性需要不高于 %d（当前 %d）", gate.Content, gate.MaxMorality, state.Status.Morality))
+			}
+		}
+	}
+	return gated
+}
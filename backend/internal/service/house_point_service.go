@@ -0,0 +1,154 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+var Houses = []string{"格兰芬多", "斯莱特林", "拉文克劳", "赫奇帕奇"}
+
+var houseAliases = map[string]string{
+	"gryffindor": "格兰芬多",
+	"slytherin":  "斯莱特林",
+	"ravenclaw":  "拉文克劳",
+	"hufflepuff": "赫奇帕奇",
+}
+
+const (
+	LeavingFeastMonth  = 6 // 6 月第 3 周：公布成绩，学年终宴，离校
+	LeavingFeastWeek   = 3
+	maxHousePointDelta = 200 // 单次加减分上限，邓布利多在终宴上也不过加了 170 分
+)
+
+// NormalizeHouse 把 "Gryffindor"、"格兰芬多学院" 等写法统一为学院中文名，无法识别时返回空
+func NormalizeHouse(name string) string {
+	name = strings.TrimSuffix(strings.TrimSpace(name), "学院")
+	if house, ok := houseAliases[strings.ToLower(name)]; ok {
+		return house
+	}
+	for _, house := range Houses {
+		if name == house {
+			return house
+		}
+	}
+	return ""
+}
+
+// isTermWeek 在校上课的周，圣诞假期与暑假不计分
+func isTermWeek(week model.GameWeek) bool {
+	switch {
+	case week.Month >= 7 && week.Month <= 8:
+		return false
+	case week.Month == 12 && week.Week >= 3:
+		return false
+	case week.Month == LeavingFeastMonth && week.Week >= LeavingFeastWeek:
+		return false
+	}
+	return true
+}
+
+// schoolYearStart 学年开始的年份，9 月开学
+func schoolYearStart(week model.GameWeek) int {
+	if week.Month < 9 {
+		return week.Year - 1
+	}
+	return week.Year
+}
+
+// schoolYearLabel 学年名称，如 1991-1992
+func schoolYearLabel(week model.GameWeek) string {
+	start := schoolYearStart(week)
+	return fmt.Sprintf("%d-%d", start, start+1)
+}
+
+func recordHousePoints(state *model.GameState, week model.GameWeek, house string, delta int, reason string, simulated bool) {
+	ledger := &state.HousePoints
+	if ledger.Totals == nil {
+		ledger.Totals = make(map[string]int)
+	}
+	ledger.Totals[house] += delta
+	ledger.Entries = append(ledger.Entries, model.HousePointEntry{
+		House:     house,
+		Turn:      state.Turn + 1,
+		Week:      week,
+		Delta:     delta,
+		Reason:    reason,
+		Simulated: simulated,
+	})
+}
+
+// applyHousePoints 记录 state_update 中叙述过的加减分
+func applyHousePoints(state *model.GameState, changes []model.HousePointChange) []string {
+	var warnings []string
+	for _, change := range changes {
+		house := change.House
+		if house == "" {
+			house = state.Profile.House
+		}
+		house = NormalizeHouse(house)
+		if house == "" {
+			warnings = append(warnings, fmt.Sprintf("无法识别学院「%s」，学院分变化未记录", change.House))
+			continue
+		}
+		delta := min(max(change.Delta, -maxHousePointDelta), maxHousePointDelta)
+		if delta != change.Delta {
+			warnings = append(warnings, fmt.Sprintf("单次学院分变化不能超过 %d 分", maxHousePointDelta))
+		}
+		recordHousePoints(state, model.WeekOf(state.Status), house, delta, change.Reason, false)
+	}
+	return warnings
+}
+
+// simulateHousePoints 模拟其他学生一周内的课堂加分与违纪扣分
+func simulateHousePoints(state *model.GameState, week model.GameWeek) {
+	for _, house := range Houses {
+		delta := rollDie(state, 31) - 6
+		recordHousePoints(state, week, house, delta, "其他学生的课堂加分与违纪扣分", true)
+	}
+}
+
+// awardHouseCup 学年终宴结算学院杯，积分并列时由并列的学院共享，并为下一学年清空积分
+func awardHouseCup(state *model.GameState, week model.GameWeek) {
+	ledger := &state.HousePoints
+	var winners []string
+	for _, house := range Houses {
+		switch {
+		case len(winners) == 0 || ledger.Totals[house] > ledger.Totals[winners[0]]:
+			winners = []string{house}
+		case ledger.Totals[house] == ledger.Totals[winners[0]]:
+			winners = append(winners, house)
+		}
+	}
+	totals := make(map[string]int, len(Houses))
+	for _, house := range Houses {
+		totals[house] = ledger.Totals[house]
+	}
+	cup := model.HouseCupResult{
+		SchoolYear: schoolYearLabel(week),
+		Winner:     strings.Join(winners, "、"),
+		Shared:     len(winners) > 1,
+		Totals:     totals,
+	}
+	ledger.Cups = append(ledger.Cups, cup)
+	if cup.Shared {
+		state.WorldLog = append(state.WorldLog, fmt.Sprintf("【学院杯】%s学年%s以 %d 分并列第一，共享学院杯", cup.SchoolYear, cup.Winner, totals[winners[0]]))
+	}
+	ledger.Totals = make(map[string]int)
+	ledger.Entries = nil
+}
+
+// houseCupThisWeek 终宴当周返回刚刚结算的学院杯，供 GM 描写
+func houseCupThisWeek(state *model.GameState) *model.HouseCupResult {
+	week := model.WeekOf(state.Status)
+	cups := state.HousePoints.Cups
+	if week.Month != LeavingFeastMonth || week.Week != LeavingFeastWeek || len(cups) == 0 {
+		return nil
+	}
+	cup := cups[len(cups)-1]
+	if cup.SchoolYear != schoolYearLabel(week) {
+		return nil
+	}
+	return &cup
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+// 物品类别
+const (
+	CategoryBook       = "book"
+	CategoryIngredient = "ingredient"
+	CategoryPet        = "pet"
+	CategoryEquipment  = "equipment"
+	CategoryArtifact   = "artifact"
+	CategoryConsumable = "consumable"
+)
+
+// 默认可堆叠的类别
+var stackableCategories = []string{CategoryIngredient, CategoryConsumable}
+
+// applyInventoryEvent 按 add/remove/use 结算一条物品栏事件，返回被驳回或修正的原因
+func applyInventoryEvent(state *model.GameState, event model.InventoryEvent) []string {
+	var warnings []string
+	if event.Quantity < 0 {
+		event.Quantity = -event.Quantity
+	}
+	item, owned := state.Inventory[event.Item]
+	switch event.Op {
+	case "add":
+		if isPurchase(event) {
+			ok, warning := applyPurchase(state, &event)
+			if warning != "" {
+				warnings = append(warnings, warning)
+			}
+			if !ok {
+				return warnings
+			}
+		}
+		state.Inventory[event.Item] = addItem(item, owned, event)
+	case "remove":
+		if !owned {
+			return warnings
+		}
+		if event.Quantity == 0 || event.Quantity >= item.Count() {
+			delete(state.Inventory, event.Item)
+			return warnings
+		}
+		item.Quantity = item.Count() - event.Quantity
+		state.Inventory[event.Item] = item
+	case "use":
+		if !owned {
+			return append(warnings, fmt.Sprintf("物品栏里没有 %s，无法使用", event.Item))
+		}
+		count := max(event.Quantity, 1)
+		switch {
+		case !item.Stackable:
+			// 装备、宠物等只有一件，一回合的效果只结算一次
+			count = 1
+		case count > item.Count():
+			warnings = append(warnings, fmt.Sprintf("%s 只剩 %d 件，按 %d 件使用", event.Item, item.Count(), item.Count()))
+			count = item.Count()
+		}
+		applyItemEffects(&state.Status, item.Effects, count)
+		// 可堆叠的消耗品用掉即减少，装备、宠物等反复使用不消耗
+		if item.Stackable {
+			if count >= item.Count() {
+				delete(state.Inventory, event.Item)
+			} else {
+				item.Quantity = item.Count() - count
+				state.Inventory[event.Item] = item
+			}
+		}
+	}
+	return warnings
+}
+
+// addItem 新物品直接入栏；已有的可堆叠物品累加数量，不可堆叠的只更新描述
+func addItem(item model.InventoryInfo, owned bool, event model.InventoryEvent) model.InventoryInfo {
+	if event.Desc != "" || !owned {
+		item.Desc = event.Desc
+	}
+	if item.Desc == "" {
+		item.Desc = "一件神秘的物品"
+	}
+	if event.Category != "" {
+		item.Category = event.Category
+	}
+	item.Stackable = itemStackable(item, owned, event)
+	if event.Effects != nil {
+		item.Effects = event.Effects
+	}
+	quantity := max(event.Quantity, 1)
+	switch {
+	case !item.Stackable:
+		item.Quantity = 1
+	case owned:
+		item.Quantity = item.Count() + quantity
+	default:
+		item.Quantity = quantity
+	}
+	return item
+}
+
+// itemStackable 事件指定的优先，已有物品沿用原设定，新物品按类别判断
+func itemStackable(item model.InventoryInfo, owned bool, event model.InventoryEvent) bool {
+	switch {
+	case event.Stackable != nil:
+		return *event.Stackable
+	case owned:
+		return item.Stackable
+	}
+	category := event.Category
+	if category == "" {
+		category = item.Category
+	}
+	return slices.Contains(stackableCategories, category)
+}
+
+// applyItemEffects 把物品效果叠加到状态上，生命不超过 100，魔力不超过上限
+func applyItemEffects(status *model.CharacterStatus, effects map[string]int, count int) {
+	for _, key := range slices.Sorted(maps.Keys(effects)) {
+		delta := effects[key] * count
+		switch key {
+		case "hp":
+			status.HP = min(max(status.HP+delta, 0), 100)
+		case "mp":
+			status.MP = min(max(status.MP+delta, 0), status.MaxMP)
+		case "knowledge":
+			status.Knowledge = max(status.Knowledge+delta, 0)
+		case "athletics":
+			status.Athletics = max(status.Athletics+delta, 0)
+		case "charm":
+			status.Charm = max(status.Charm+delta, 0)
+		case "morality":
+			status.Morality = max(status.Morality+delta, 0)
+		case "mental":
+			status.Mental = max(status.Mental+delta, 0)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+const maxKnownNames = 200
+
+// 开学宴与课堂上会公开介绍的人物
+var introducedRoles = []string{"校长", "教授", "教师", "管理员", "校医", "图书管理员", "猎场看守", "幽灵"}
+
+type nameCandidate struct {
+	Text  string
+	Entry model.NPCEntry
+}
+
+// npcNameCandidates 全部原著人物的全名与别名，长的在前，单字别名太容易误判不参与检查
+var npcNameCandidates = func() []nameCandidate {
+	candidates := []nameCandidate{}
+	for _, entry := range npcCatalog {
+		for _, name := range append([]string{entry.Name}, entry.Aliases...) {
+			if utf8.RuneCountInString(name) >= 2 {
+				candidates = append(candidates, nameCandidate{Text: name, Entry: entry})
+			}
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b nameCandidate) int {
+		return cmp.Compare(utf8.RuneCountInString(b.Text), utf8.RuneCountInString(a.Text))
+	})
+	return candidates
+}()
+
+// learnNames 记下主角新认识的人物，原著人物统一记为全名
+func learnNames(state *model.GameState, names []string) {
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if entry, ok := LookupNPC(name); ok {
+			name = entry.Name
+		}
+		if name != "" && !slices.Contains(state.KnownNames, name) {
+			state.KnownNames = append(state.KnownNames, name)
+		}
+	}
+	if len(state.KnownNames) > maxKnownNames {
+		state.KnownNames = state.KnownNames[len(state.KnownNames)-maxKnownNames:]
+	}
+}
+
+// knowsNPC 主角是否知道这个原著人物的名字：名人、公开点过名的人物，或已记入已知人名、羁绊的人物
+func knowsNPC(state *model.GameState, entry model.NPCEntry) bool {
+	if publiclyKnown(state, entry) {
+		return true
+	}
+	for _, name := range state.KnownNames {
+		if known, ok := LookupNPC(name); ok && known.Name == entry.Name {
+			return true
+		}
+	}
+	for name := range state.Relationships {
+		if known, ok := LookupNPC(name); ok && known.Name == entry.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// publiclyKnown 分院之后，同届新生在分院仪式上被点过名，在校教职工与幽灵也会在开学宴和课堂上露面
+func publiclyKnown(state *model.GameState, entry model.NPCEntry) bool {
+	if entry.Famous {
+		return true
+	}
+	if NormalizeHouse(state.Profile.House) == "" {
+		return false
+	}
+	week := model.WeekOf(state.Status)
+	start := schoolYearStart(week)
+	if start < entry.SchoolFrom || start > entry.SchoolTo {
+		return false
+	}
+	if entry.Role == "学生" {
+		return entry.SchoolFrom == start-SchoolYear(state.Status)+1
+	}
+	return slices.Contains(introducedRoles, entry.Role)
+}
+
+// KnownNames 正文可以直呼其名的人物：已知人名、羁绊人物，加上本周在场、公开介绍过的原著人物
+func KnownNames(state model.GameState) []string {
+	names := slices.Clone(state.KnownNames)
+	for _, name := range slices.Sorted(maps.Keys(state.Relationships)) {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	for _, member := range (&NPCService{}).Cast(state) {
+		entry, _ := LookupNPC(member.Name)
+		if publiclyKnown(&state, entry) && !slices.Contains(names, entry.Name) {
+			names = append(names, entry.Name)
+		}
+	}
+	return names
+}
+
+// unknownNamesIn 找出正文里出现的、主角尚不认识的原著人物；长名字先匹配，避免「汤姆·里德尔」被当成「汤姆」
+func unknownNamesIn(state *model.GameState, reply string) []string {
+	text := stateUpdatePattern.ReplaceAllString(reply, "")
+	unknown := []string{}
+	for _, candidate := range npcNameCandidates {
+		if !strings.Contains(text, candidate.Text) {
+			continue
+		}
+		text = strings.ReplaceAll(text, candidate.Text, "\x00")
+		if !knowsNPC(state, candidate.Entry) && !slices.Contains(unknown, candidate.Entry.Name) {
+			unknown = append(unknown, candidate.Entry.Name)
+		}
+	}
+	return unknown
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/config"
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+// 地点限制类型
+const (
+	RestrictionForbidden  = "forbidden"
+	RestrictionHouse      = "house"
+	RestrictionStaff      = "staff"
+	RestrictionPermission = "permission"
+	RestrictionYear       = "year"
+)
+
+const (
+	regionHogwarts   = "霍格沃茨"
+	curfewRule       = "宵禁后离开宿舍"
+	leaveSchoolRule  = "学期中擅自离校"
+	maxRuleBreaks    = 100
+	locationSplitter = "·"
+	regionQualifiers = "·・ 的" // 区域与地点之间可以出现的连接符
+)
+
+var locationCatalog = mustLoadCatalog[[]model.LocationNode]("地点图", config.LocationCatalog)
+
+type LocationService struct{}
+
+// LocationCheck 前往某地的预检结果
+type LocationCheck struct {
+	Location string              `json:"location"` // 规范化后的地点
+	Node     *model.LocationNode `json:"node,omitempty"`
+	Route    []string            `json:"route"`  // 从当前位置出发的路线，地点不在图上时为空
+	Breaks   []string            `json:"breaks"` // 前往该地会违反的校规
+}
+
+// Graph 全部地点及其连通关系
+func (s *LocationService) Graph() []model.LocationNode {
+	return locationCatalog
+}
+
+// Check 不改动存档，预先判断前往某地的路线与会违反的校规
+func (s *LocationService) Check(state model.GameState, location string, night bool) LocationCheck {
+	check := LocationCheck{Location: NormalizeLocation(location), Route: []string{}, Breaks: []string{}}
+	node, ok := LookupLocation(location)
+	if !ok {
+		return check
+	}
+	check.Node = &node
+	if from, ok := LookupLocation(state.Status.Location); ok {
+		check.Route = locationRoute(from.Name, node.Name)
+	}
+	check.Breaks = append(check.Breaks, locationBreaks(&state, node, night)...)
+	return check
+}
+
+// LookupLocation 按名字、别名或「区域·地点」精确查找地点，区域限定可以省略，
+// 如「礼堂」「霍格沃茨·礼堂」「对角巷的古灵阁」。自由描述不做模糊匹配，
+// 以免「对角巷某家店的地下室」被当成城堡地牢
+func LookupLocation(text string) (model.LocationNode, bool) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return model.LocationNode{}, false
+	}
+	for _, node := range locationCatalog {
+		if locationNamed(node, text) || text == locationLabel(node) {
+			return node, true
+		}
+	}
+	for _, node := range locationCatalog {
+		if node.Region == "" {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(text, node.Region); ok && locationNamed(node, strings.TrimLeft(rest, regionQualifiers)) {
+			return node, true
+		}
+	}
+	return model.LocationNode{}, false
+}
+
+func locationNamed(node model.LocationNode, name string) bool {
+	return node.Name == name || slices.Contains(node.Aliases, name)
+}
+
+// NormalizeLocation 图上的地点统一写成「区域·地点」，不在图上的地点原样保留
+func NormalizeLocation(text string) string {
+	if node, ok := LookupLocation(text); ok {
+		return locationLabel(node)
+	}
+	return strings.TrimSpace(text)
+}
+
+// locationLabel 区域名已经包含在地点名里时不再重复
+func locationLabel(node model.LocationNode) string {
+	if node.Region == "" || strings.Contains(node.Name, node.Region) {
+		return node.Name
+	}
+	return node.Region + locationSplitter + node.Name
+}
+
+// locationRoute 两地之间经过地点最少的路线，含起点和终点
+func locationRoute(from, to string) []string {
+	links := make(map[string][]string, len(locationCatalog))
+	for _, node := range locationCatalog {
+		links[node.Name] = node.Links
+	}
+	previous := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 && previous[to] == "" && from != to {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range links[current] {
+			if _, seen := previous[next]; !seen {
+				previous[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+	if _, ok := previous[to]; !ok {
+		return []string{}
+	}
+	route := []string{}
+	for name := to; name != ""; name = previous[name] {
+		route = append(route, name)
+	}
+	slices.Reverse(route)
+	return route
+}
+
+// locationBreaks 角色出现在该地点会违反的校规
+func locationBreaks(state *model.GameState, node model.LocationNode, night bool) []string {
+	var breaks []string
+	week := model.WeekOf(state.Status)
+	house := NormalizeHouse(state.Profile.House)
+	ownCommonRoom := false
+	if r := node.Restriction; r != nil {
+		start := schoolYearStart(week)
+		active := (r.From == 0 && r.To == 0) || (start >= r.From && start <= r.To)
+		switch {
+		case !active:
+		case r.Kind == RestrictionHouse && r.House == house:
+			ownCommonRoom = true
+		case r.Kind == RestrictionYear && (!isTermWeek(week) || SchoolYear(state.Status) >= r.MinYear):
+		default:
+			breaks = append(breaks, r.Rule)
+		}
+	}
+	if isTermWeek(week) && (node.Region == "对角巷" || (node.Region == "伦敦" && node.Name != "国王十字车站")) {
+		breaks = append(breaks, leaveSchoolRule)
+	}
+	if night && node.Region == regionHogwarts && !node.NightOK && !ownCommonRoom {
+		breaks = append(breaks, curfewRule)
+	}
+	return breaks
+}
+
+// isNight 玩家指令里写明在宵禁后行动，「宵禁前回去」「不想深夜出门」这类说法不算
+func isNight(input string) bool {
+	return len(affirmedClauses(input, "夜游", "深夜", "半夜", "午夜", "凌晨", "熄灯后", "宵禁后", "宵禁以后")) > 0
+}
+
+// recordMovement 结算后检查角色所在地点：换了地点或夜间行动时按地点规则记录违规
+func recordMovement(state *model.GameState, before string, night bool) []string {
+	node, ok := LookupLocation(state.Status.Location)
+	if !ok || (state.Status.Location == before && !night) {
+		return nil
+	}
+	var warnings []string
+	for _, rule := range locationBreaks(state, node, night) {
+		state.RuleBreaks = append(state.RuleBreaks, model.RuleBreak{
+			Turn:     state.Turn + 1,
+			Week:     model.WeekOf(state.Status),
+			Location: state.Status.Location,
+			Rule:     rule,
+		})
+		warnings = append(warnings, fmt.Sprintf("在%s违反校规：%s", state.Status.Location, rule))
+	}
+	if len(state.RuleBreaks) > maxRuleBreaks {
+		state.RuleBreaks = state.RuleBreaks[len(state.RuleBreaks)-maxRuleBreaks:]
+	}
+	return warnings
+}
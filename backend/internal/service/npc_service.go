@@ -0,0 +1,85 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/config"
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+var npcCatalog = mustLoadCatalog[[]model.NPCEntry]("原著人物", config.NPCCatalog)
+
+type NPCService struct{}
+
+// Registry 全部原著人物
+func (s *NPCService) Registry() []model.NPCEntry {
+	return npcCatalog
+}
+
+// Cast 角色当前所处的这一周可以登场的原著人物
+func (s *NPCService) Cast(state model.GameState) []model.CastMember {
+	week := model.WeekOf(state.Status)
+	cast := []model.CastMember{}
+	for _, entry := range npcCatalog {
+		entry = spareNPC(&state, entry)
+		if location, reason := npcPresence(entry, week); reason == "" {
+			cast = append(cast, model.CastMember{Name: entry.Name, Role: entry.Role, House: entry.House, Location: location})
+		}
+	}
+	return cast
+}
+
+// npcPresence 人物在某一周的所在地；不能登场时返回原因
+func npcPresence(entry model.NPCEntry, week model.GameWeek) (string, string) {
+	if entry.Born > 0 && week.Year < entry.Born {
+		return "", fmt.Sprintf("%d 年才出生", entry.Born)
+	}
+	if entry.Died != nil && week.Index() >= entry.Died.Index() {
+		return "", fmt.Sprintf("已于 %s 去世", entry.Died)
+	}
+	if entry.SchoolFrom == 0 && entry.SchoolTo == 0 {
+		return entry.Location, ""
+	}
+	start := schoolYearStart(week)
+	switch {
+	case start < entry.SchoolFrom && entry.Before == "":
+		return "", fmt.Sprintf("%d-%d 学年才来到霍格沃茨", entry.SchoolFrom, entry.SchoolFrom+1)
+	case start < entry.SchoolFrom:
+		return entry.Before, ""
+	case start > entry.SchoolTo && entry.After == "":
+		return "", fmt.Sprintf("%d-%d 学年结束后已离开霍格沃茨", entry.SchoolTo, entry.SchoolTo+1)
+	case start > entry.SchoolTo:
+		return entry.After, ""
+	}
+	return entry.Location, ""
+}
+
+// LookupNPC 按全名、别名查找原著人物，名字里带有全名(如「麦格教授」)也算
+func LookupNPC(name string) (model.NPCEntry, bool) {
+	name = strings.TrimSpace(name)
+	for _, entry := range npcCatalog {
+		if entry.Name == name || slices.Contains(entry.Aliases, name) {
+			return entry, true
+		}
+	}
+	for _, entry := range npcCatalog {
+		if strings.Contains(name, entry.Name) {
+			return entry, true
+		}
+	}
+	return model.NPCEntry{}, false
+}
+
+// npcAbsence 原著人物此时不应登场时返回提示，自创人物与可以登场的人物返回空
+func npcAbsence(state *model.GameState, name string) string {
+	entry, ok := LookupNPC(name)
+	if !ok {
+		return ""
+	}
+	if _, reason := npcPresence(spareNPC(state, entry), model.WeekOf(state.Status)); reason != "" {
+		return fmt.Sprintf("「%s」此时不应登场：%s", name, reason)
+	}
+	return ""
+}
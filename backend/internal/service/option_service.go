@@ -0,0 +1,101 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+var (
+	// Part 2 的标题，如 "Part 2: 9月第2周行动建议"、"[场景互动窗口]"
+	optionSectionPattern = regexp.MustCompile(`(?i)part\s*2|行动建议|场景互动窗口`)
+	// 行首的类别标签，如 "[学业]"、"【剧情】"
+	optionCategoryPattern = regexp.MustCompile(`^[\[【]([^\]】]{1,8})[\]】]\s*`)
+	// 行首的列表符号，如 "- "、"1. "、"A、"
+	optionBulletPattern = regexp.MustCompile(`^(?:[-*•]\s*|\d+[.、)）]\s*|[A-Da-d][.、)）]\s*)`)
+)
+
+// ParseActionOptions 从 GM 回复的 Part 2 中解析出结构化的行动选项
+func ParseActionOptions(reply string, eventMode bool) []model.ActionOption {
+	text := stateUpdatePattern.ReplaceAllString(reply, "")
+	if open := strings.Index(text, "<state_update>"); open != -1 {
+		text = text[:open]
+	}
+	section := text
+	if loc := lastIndex(optionSectionPattern, text); loc != -1 {
+		section = text[loc:]
+		if strings.Contains(section[:min(len(section), 64)], "场景互动") {
+			eventMode = true
+		}
+	}
+
+	options := []model.ActionOption{}
+	for i, line := range strings.Split(section, "\n") {
+		line = strings.TrimSpace(strings.ReplaceAll(line, "**", ""))
+		line = optionBulletPattern.ReplaceAllString(line, "")
+		if line == "" || strings.HasPrefix(line, ">") || strings.HasSuffix(line, "：") || strings.HasSuffix(line, ":") || (i == 0 && section != text) {
+			continue
+		}
+		category := ""
+		if match := optionCategoryPattern.FindStringSubmatch(line); match != nil {
+			category = match[1]
+			line = line[len(match[0]):]
+		} else if !eventMode {
+			// 周常模式下只认带类别标签的行，避免把说明文字当成选项
+			continue
+		}
+		for _, part := range strings.FieldsFunc(line, func(r rune) bool { return r == '/' || r == '／' }) {
+			option := parseOption(category, part, eventMode)
+			if option.Label != "" {
+				options = append(options, option)
+			}
+		}
+	}
+	return options
+}
+
+func parseOption(category, text string, eventMode bool) model.ActionOption {
+	option := model.ActionOption{Category: category, IsEvent: eventMode}
+	if option.Category == "" {
+		option.Category = "事件"
+	}
+	if !eventMode {
+		option.APCost = ParseAPCost(text)
+	}
+	text = apCostPattern.ReplaceAllString(text, "")
+	option.Label = strings.Trim(strings.TrimSpace(text), "：:，,。")
+	return option
+}
+
+// ResolveActionCost 计算玩家指令的 AP 消耗：命中建议选项的按选项标价计算，
+// 其余自定义行动按玩家自己标注的消耗计算
+func ResolveActionCost(input string, pending []model.ActionOption) (int, []model.ActionOption) {
+	cost := 0
+	chosen := []model.ActionOption{}
+	for _, option := range pending {
+		if option.Label == "" {
+			continue
+		}
+		start := strings.Index(input, option.Label)
+		if start == -1 {
+			continue
+		}
+		end := start + len(option.Label)
+		if loc := apCostPattern.FindStringIndex(input[end:]); loc != nil && strings.TrimSpace(input[end:end+loc[0]]) == "" {
+			end += loc[1]
+		}
+		input = input[:start] + input[end:]
+		cost += option.APCost
+		chosen = append(chosen, option)
+	}
+	return cost + ParseAPCost(input), chosen
+}
+
+func lastIndex(pattern *regexp.Regexp, text string) int {
+	matches := pattern.FindAllStringIndex(text, -1)
+	if len(matches) == 0 {
+		return -1
+	}
+	return matches[len(matches)-1][0]
+}
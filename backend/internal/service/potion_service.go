@@ -0,0 +1,173 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/config"
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+// 熬制品质
+const (
+	BrewPerfect  = "perfect"
+	BrewStandard = "standard"
+	BrewPoor     = "poor"
+	BrewFailed   = "failed"
+)
+
+const (
+	potionSkill   = "魔药学"
+	perfectMargin = 10 // 检定超出 DC 10 点以上熬出优质成品
+)
+
+var potionCatalog = mustLoadCatalog[[]model.PotionRecipe]("魔药配方", config.PotionCatalog)
+
+type PotionService struct{}
+
+// BrewableRecipe 配方及角色还缺的原料
+type BrewableRecipe struct {
+	model.PotionRecipe
+	Missing []model.Ingredient `json:"missing"`
+}
+
+// Recipes 全部魔药配方
+func (s *PotionService) Recipes() []model.PotionRecipe {
+	return potionCatalog
+}
+
+// Available 当前年级能熬制的配方，并列出物品栏里还缺的原料
+func (s *PotionService) Available(state model.GameState) []BrewableRecipe {
+	year := SchoolYear(state.Status)
+	recipes := []BrewableRecipe{}
+	for _, recipe := range potionCatalog {
+		if year >= recipe.MinYear {
+			recipes = append(recipes, BrewableRecipe{PotionRecipe: recipe, Missing: missingIngredients(state.Inventory, recipe)})
+		}
+	}
+	return recipes
+}
+
+// Brew 消耗原料并按熬制检定决定成品品质，成品直接放入物品栏；失败时原料同样损失
+func (s *PotionService) Brew(state *model.GameState, recipe model.PotionRecipe) *model.BrewResult {
+	result := &model.BrewResult{Recipe: recipe.Name}
+	switch {
+	case SchoolYear(state.Status) < recipe.MinYear:
+		result.Note = fmt.Sprintf("%s需要 %d 年级以上才能熬制", recipe.Name, recipe.MinYear)
+		return result
+	case !hasCauldron(state.Inventory):
+		result.Note = "物品栏里没有坩埚，无法熬制"
+		return result
+	}
+	if result.Missing = missingIngredients(state.Inventory, recipe); len(result.Missing) > 0 {
+		result.Note = "原料不足，无法开始熬制"
+		return result
+	}
+	for _, ingredient := range recipe.Ingredients {
+		applyInventoryEvent(state, model.InventoryEvent{Op: "remove", Item: ingredient.Name, Quantity: ingredientCount(ingredient)})
+		result.Consumed = append(result.Consumed, ingredient)
+	}
+
+	check := BrewCheck(state, recipe)
+	result.Check = &check
+	name, quantity, scale := recipe.Name, max(recipe.Yield, 1), 1.0
+	switch {
+	case check.Outcome == OutcomeSuccess && check.Total >= check.DC+perfectMargin:
+		result.Quality = BrewPerfect
+		name, quantity, scale = "优质"+recipe.Name, quantity+1, 1.5
+	case check.Outcome == OutcomeSuccess:
+		result.Quality = BrewStandard
+	case check.Outcome == OutcomeNearMiss:
+		result.Quality = BrewPoor
+		name, scale = "劣质"+recipe.Name, 0.5
+	default:
+		result.Quality = BrewFailed
+		result.Check.Effect = "熬制失败，原料全部报废"
+		return result
+	}
+
+	effects := make(map[string]int, len(recipe.Effects))
+	for key, value := range recipe.Effects {
+		effects[key] = int(math.Round(float64(value) * scale))
+	}
+	stackable := true
+	item, owned := state.Inventory[name]
+	state.Inventory[name] = addItem(item, owned, model.InventoryEvent{
+		Op:        "add",
+		Item:      name,
+		Desc:      recipe.Desc,
+		Quantity:  quantity,
+		Category:  CategoryConsumable,
+		Stackable: &stackable,
+		Effects:   effects,
+	})
+	result.Potion, result.Quantity, result.Effects = name, quantity, effects
+	result.Check.Effect = fmt.Sprintf("获得%s×%d", name, quantity)
+	return result
+}
+
+// BrewCheck 熬制检定: (学识×0.2) + (心智×0.3) + 魔药学熟练度加成 + D20
+func BrewCheck(state *model.GameState, recipe model.PotionRecipe) model.CheckResult {
+	status := state.Status
+	modifier := float64(status.Knowledge)*0.2 + float64(status.Mental)*0.3 + levelBonus(state.Spells[potionSkill].Level)
+	return settle(state, model.CheckResult{Kind: "熬制:" + recipe.Name, Attribute: "knowledge/mental", Dice: "D20", Modifier: modifier, DC: recipe.DC}, 20)
+}
+
+func ingredientCount(ingredient model.Ingredient) int {
+	return max(ingredient.Quantity, 1)
+}
+
+func missingIngredients(inventory model.InventoryMap, recipe model.PotionRecipe) []model.Ingredient {
+	missing := []model.Ingredient{}
+	for _, ingredient := range recipe.Ingredients {
+		have := 0
+		if item, ok := inventory[ingredient.Name]; ok {
+			have = item.Count()
+		}
+		if need := ingredientCount(ingredient); have < need {
+			missing = append(missing, model.Ingredient{Name: ingredient.Name, Quantity: need - have})
+		}
+	}
+	return missing
+}
+
+func hasCauldron(inventory model.InventoryMap) bool {
+	for name := range inventory {
+		if strings.Contains(name, "坩埚") {
+			return true
+		}
+	}
+	return false
+}
+
+// brewRecipe 玩家指令的同一分句里明确要熬制并点名了配方时返回该配方，「先别熬煮」这类否定说法不算
+func brewRecipe(input string) (model.PotionRecipe, bool) {
+	for _, clause := range affirmedClauses(input, "熬制", "熬煮", "酿制", "调制") {
+		for _, recipe := range potionCatalog {
+			if strings.Contains(clause, recipe.Name) {
+				return recipe, true
+			}
+		}
+	}
+	return model.PotionRecipe{}, false
+}
+
+// dropBrewEvents 熬制的原料与成品已由规则结算，去掉模型对这些物品的重复增减
+func dropBrewEvents(events []model.InventoryEvent, brew *model.BrewResult) []model.InventoryEvent {
+	handled := []string{}
+	for _, ingredient := range brew.Consumed {
+		handled = append(handled, ingredient.Name)
+	}
+	if brew.Quality != "" {
+		handled = append(handled, brew.Recipe, brew.Potion)
+	}
+	kept := []model.InventoryEvent{}
+	for _, event := range events {
+		if event.Op == "use" || !slices.Contains(handled, event.Item) {
+			kept = append(kept, event)
+		}
+	}
+	return kept
+}
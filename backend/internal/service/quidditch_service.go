@@ -0,0 +1,308 @@
+package service
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/config"
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+// 魁地奇位置
+const (
+	PositionChaser = "追球手"
+	PositionBeater = "击球手"
+	PositionKeeper = "守门员"
+	PositionSeeker = "找球手"
+)
+
+const (
+	quidditchWinPoints    = 30 // 获胜学院的学院分
+	goalPoints            = 10
+	snitchPoints          = 150
+	matchRounds           = 10
+	snitchFromRound       = 4  // 前几轮找球手还在盘旋寻找飞贼
+	snitchCatchTotal      = 24 // D20 + 找球手水平/10 达到该值即抓住飞贼
+	goalThreshold         = 55
+	minQuidditchYear      = 2 // 一年级新生不能入选学院队
+	minQuidditchAthletics = 40
+	benchRating           = 45
+	maxMatchResults       = 50
+)
+
+var quidditchCatalog = mustLoadCatalog[model.QuidditchCatalog]("魁地奇赛程", config.QuidditchCatalog)
+
+type positionSlot struct {
+	Position string
+	Count    int
+}
+
+// 每支球队的编制：三名追球手、两名击球手、一名守门员、一名找球手
+var positionSlots = []positionSlot{
+	{PositionChaser, 3}, {PositionBeater, 2}, {PositionKeeper, 1}, {PositionSeeker, 1},
+}
+
+type QuidditchService struct{}
+
+// QuidditchSeason 本赛季的阵容、赛程与积分
+type QuidditchSeason struct {
+	Season    string                  `json:"season"`
+	Cancelled bool                    `json:"cancelled"`
+	Team      []model.QuidditchPlayer `json:"team"` // 玩家所在学院的阵容
+	Fixtures  []SeasonFixture         `json:"fixtures"`
+	Standings map[string]int          `json:"standings"` // 各学院本赛季累计得分
+}
+
+type SeasonFixture struct {
+	Week   model.GameWeek     `json:"week"`
+	Home   string             `json:"home"`
+	Away   string             `json:"away"`
+	Result *model.MatchResult `json:"result,omitempty"`
+}
+
+// Season 角色当前所在赛季的概况
+func (s *QuidditchService) Season(state model.GameState) QuidditchSeason {
+	now := model.WeekOf(state.Status)
+	season := QuidditchSeason{
+		Season:    schoolYearLabel(now),
+		Cancelled: slices.Contains(quidditchCatalog.Cancelled, schoolYearLabel(now)),
+		Team:      []model.QuidditchPlayer{},
+		Fixtures:  []SeasonFixture{},
+		Standings: make(map[string]int, len(Houses)),
+	}
+	if house := NormalizeHouse(state.Profile.House); house != "" {
+		season.Team = teamRoster(&state, house, now)
+	}
+	for _, house := range Houses {
+		season.Standings[house] = 0
+	}
+	for _, fixture := range quidditchCatalog.Fixtures {
+		entry := SeasonFixture{Week: fixtureWeek(fixture, now), Home: fixture.Home, Away: fixture.Away}
+		for i, result := range state.Quidditch.Results {
+			if result.Season == season.Season && result.Home == fixture.Home && result.Away == fixture.Away {
+				entry.Result = &state.Quidditch.Results[i]
+				season.Standings[result.Home] += result.HomeScore
+				season.Standings[result.Away] += result.AwayScore
+			}
+		}
+		season.Fixtures = append(season.Fixtures, entry)
+	}
+	return season
+}
+
+// fixtureWeek 赛程在本学年的具体周，11 月至 12 月在学年开始那一年，其余在下一年
+func fixtureWeek(fixture model.QuidditchFixture, now model.GameWeek) model.GameWeek {
+	year := schoolYearStart(now)
+	if fixture.Month < 9 {
+		year++
+	}
+	return model.GameWeek{Year: year, Month: fixture.Month, Week: fixture.Week}
+}
+
+// teamRoster 某学院本赛季的七人阵容，原著队员不足时由替补补齐；玩家入队后顶替同位置水平最低的队员
+func teamRoster(state *model.GameState, house string, week model.GameWeek) []model.QuidditchPlayer {
+	start := schoolYearStart(week)
+	candidates := []model.QuidditchPlayer{}
+	for _, player := range quidditchCatalog.Players {
+		if player.House == house && start >= player.From && start <= player.To {
+			candidates = append(candidates, player)
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b model.QuidditchPlayer) int { return cmp.Compare(b.Rating, a.Rating) })
+
+	joined := state.Quidditch.Position != "" && NormalizeHouse(state.Profile.House) == house
+	roster := []model.QuidditchPlayer{}
+	for _, slot := range positionSlots {
+		count := slot.Count
+		if joined && slot.Position == state.Quidditch.Position {
+			count--
+			roster = append(roster, model.QuidditchPlayer{
+				Name:     playerName(state),
+				House:    house,
+				Position: slot.Position,
+				From:     start,
+				To:       start,
+				Rating:   state.Status.Athletics,
+			})
+		}
+		for _, player := range candidates {
+			if count > 0 && player.Position == slot.Position {
+				roster = append(roster, player)
+				count--
+			}
+		}
+		for ; count > 0; count-- {
+			roster = append(roster, model.QuidditchPlayer{Name: "替补" + slot.Position, House: house, Position: slot.Position, From: start, To: start, Rating: benchRating})
+		}
+	}
+	return roster
+}
+
+func playerName(state *model.GameState) string {
+	if state.Profile.Name == "" {
+		return "你"
+	}
+	return state.Profile.Name
+}
+
+// playMatchesThisWeek 赛程落在这一周时模拟比赛，获胜学院加分，平局不加分
+func playMatchesThisWeek(state *model.GameState, week model.GameWeek) {
+	if slices.Contains(quidditchCatalog.Cancelled, schoolYearLabel(week)) {
+		return
+	}
+	for _, fixture := range quidditchCatalog.Fixtures {
+		if fixture.Month != week.Month || fixture.Week != week.Week {
+			continue
+		}
+		result := simulateMatch(state, fixture, week)
+		state.Quidditch.Results = append(state.Quidditch.Results, result)
+		if len(state.Quidditch.Results) > maxMatchResults {
+			state.Quidditch.Results = state.Quidditch.Results[len(state.Quidditch.Results)-maxMatchResults:]
+		}
+		if result.Winner == "" {
+			continue
+		}
+		reason := fmt.Sprintf("魁地奇比赛 %s %d:%d %s", result.Home, result.HomeScore, result.AwayScore, result.Away)
+		recordHousePoints(state, week, result.Winner, quidditchWinPoints, reason, true)
+	}
+}
+
+// simulateMatch 按双方阵容逐轮模拟进攻与找球手争夺飞贼，抓住飞贼后比赛结束
+func simulateMatch(state *model.GameState, fixture model.QuidditchFixture, week model.GameWeek) model.MatchResult {
+	teams := map[string][]model.QuidditchPlayer{
+		fixture.Home: teamRoster(state, fixture.Home, week),
+		fixture.Away: teamRoster(state, fixture.Away, week),
+	}
+	result := model.MatchResult{
+		Season:     schoolYearLabel(week),
+		Week:       week,
+		Home:       fixture.Home,
+		Away:       fixture.Away,
+		Highlights: []string{},
+	}
+	name := playerName(state)
+	result.PlayerPlayed = state.Quidditch.Position != "" && slices.Contains([]string{fixture.Home, fixture.Away}, NormalizeHouse(state.Profile.House))
+	scores := map[string]int{}
+	goals, saves := 0, 0
+	for round := 1; round <= matchRounds && result.SnitchCaughtBy == ""; round++ {
+		for _, side := range [][2]string{{fixture.Home, fixture.Away}, {fixture.Away, fixture.Home}} {
+			attackers, defenders := teams[side[0]], teams[side[1]]
+			chasers := atPosition(attackers, PositionChaser)
+			chaser := chasers[rollDie(state, len(chasers))-1]
+			keeper := atPosition(defenders, PositionKeeper)[0]
+			attack := float64(chaser.Rating) + averageRating(atPosition(attackers, PositionBeater))*0.3
+			defence := float64(keeper.Rating) + averageRating(atPosition(defenders, PositionBeater))*0.3
+			if float64(rollDie(state, 100))+attack-defence > goalThreshold {
+				scores[side[0]] += goalPoints
+				if chaser.Name == name {
+					goals++
+				}
+			} else if keeper.Name == name {
+				saves++
+			}
+		}
+		if round < snitchFromRound {
+			continue
+		}
+		homeSeeker := atPosition(teams[fixture.Home], PositionSeeker)[0]
+		awaySeeker := atPosition(teams[fixture.Away], PositionSeeker)[0]
+		homeRoll := rollDie(state, 20) + homeSeeker.Rating/10
+		awayRoll := rollDie(state, 20) + awaySeeker.Rating/10
+		if max(homeRoll, awayRoll) < snitchCatchTotal && round < matchRounds {
+			continue
+		}
+		// 两名找球手同时扑向飞贼时重掷，直到分出先后
+		for homeRoll == awayRoll {
+			homeRoll = rollDie(state, 20) + homeSeeker.Rating/10
+			awayRoll = rollDie(state, 20) + awaySeeker.Rating/10
+		}
+		catcher, house := homeSeeker, fixture.Home
+		if awayRoll > homeRoll {
+			catcher, house = awaySeeker, fixture.Away
+		}
+		scores[house] += snitchPoints
+		result.SnitchCaughtBy = catcher.Name
+		result.Highlights = append(result.Highlights, fmt.Sprintf("第%d轮 %s的%s抓住了金色飞贼", round, house, catcher.Name))
+	}
+	if goals > 0 {
+		result.Highlights = append(result.Highlights, fmt.Sprintf("%s打进 %d 球", name, goals))
+	}
+	if saves > 0 {
+		result.Highlights = append(result.Highlights, fmt.Sprintf("%s扑出 %d 次射门", name, saves))
+	}
+	result.HomeScore, result.AwayScore = scores[fixture.Home], scores[fixture.Away]
+	caughtBy := func(house string) bool {
+		return slices.ContainsFunc(teams[house], func(p model.QuidditchPlayer) bool { return p.Name == result.SnitchCaughtBy })
+	}
+	// 比分相同时抓住飞贼的一方获胜，没人抓住飞贼则为平局
+	switch {
+	case result.HomeScore > result.AwayScore || result.HomeScore == result.AwayScore && caughtBy(fixture.Home):
+		result.Winner = fixture.Home
+	case result.AwayScore > result.HomeScore || result.HomeScore == result.AwayScore && caughtBy(fixture.Away):
+		result.Winner = fixture.Away
+	}
+	return result
+}
+
+func atPosition(roster []model.QuidditchPlayer, position string) []model.QuidditchPlayer {
+	players := []model.QuidditchPlayer{}
+	for _, player := range roster {
+		if player.Position == position {
+			players = append(players, player)
+		}
+	}
+	return players
+}
+
+func averageRating(players []model.QuidditchPlayer) float64 {
+	if len(players) == 0 {
+		return 0
+	}
+	total := 0
+	for _, player := range players {
+		total += player.Rating
+	}
+	return float64(total) / float64(len(players))
+}
+
+// applyQuidditchChange 入队需要二年级以上且体能达标，位置必须是四个位置之一
+func applyQuidditchChange(state *model.GameState, change *model.QuidditchChange) []string {
+	if change == nil {
+		return nil
+	}
+	if change.Leave {
+		state.Quidditch.Position = ""
+		return nil
+	}
+	if change.Join == "" {
+		return nil
+	}
+	switch {
+	case !slices.ContainsFunc(positionSlots, func(slot positionSlot) bool { return slot.Position == change.Join }):
+		return []string{fmt.Sprintf("魁地奇没有「%s」这个位置", change.Join)}
+	case graduated(state):
+		return []string{"毕业生不能再加入学院魁地奇队"}
+	case NormalizeHouse(state.Profile.House) == "":
+		return []string{"分院之前不能加入学院魁地奇队"}
+	case SchoolYear(state.Status) < minQuidditchYear:
+		return []string{"一年级新生不能加入学院魁地奇队"}
+	case state.Status.Athletics < minQuidditchAthletics:
+		return []string{fmt.Sprintf("体能 %d 未达到选拔要求 %d，无法入队", state.Status.Athletics, minQuidditchAthletics)}
+	}
+	state.Quidditch.Position = change.Join
+	return nil
+}
+
+// matchThisWeek 本周刚刚结束的比赛，供 GM 描写
+func matchThisWeek(state *model.GameState) *model.MatchResult {
+	results := state.Quidditch.Results
+	if len(results) == 0 {
+		return nil
+	}
+	result := results[len(results)-1]
+	if result.Week != model.WeekOf(state.Status) {
+		return nil
+	}
+	return &result
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+const (
+	MaxRelationLevel   = 5
+	SoulBondLevel      = 5 // LV5 灵魂羁绊，只能有一个对象
+	SoulBondYear       = 5 // LV5 最早解锁的年级
+	ConfidantYear      = 3 // LV4 最早解锁的年级
+	maxRelationHistory = 50
+)
+
+type RelationshipService struct{}
+
+// Timeline 某个角色的羁绊变化轨迹，name 为空时返回全部角色
+func (s *RelationshipService) Timeline(state model.GameState, name string) map[string][]model.RelationChange {
+	if name == "" {
+		if state.RelationshipHistory == nil {
+			return map[string][]model.RelationChange{}
+		}
+		return state.RelationshipHistory
+	}
+	return map[string][]model.RelationChange{name: state.RelationshipHistory[name]}
+}
+
+// relationCeiling 学年天花板: 1-2 年级 LV3.5，3-4 年级 LV4.5，5 年级起解锁 LV5
+func relationCeiling(year int) float64 {
+	switch {
+	case year < ConfidantYear:
+		return 3.5
+	case year < SoulBondYear:
+		return 4.5
+	}
+	return MaxRelationLevel
+}
+
+// applyRelationshipUpdate 按年级与唯一性约束应用羁绊变化并记录轨迹，返回被修正的原因
+func applyRelationshipUpdate(state *model.GameState, name string, info model.RelationInfo) string {
+	warning := ""
+	current, known := state.Relationships[name]
+	level := max(info.Level, 0)
+
+	if ceiling := relationCeiling(SchoolYear(state.Status)); level > ceiling {
+		level = ceiling
+		warning = fmt.Sprintf("%d 年级与「%s」的羁绊最高只能到 LV%g", SchoolYear(state.Status), name, ceiling)
+	}
+	if level >= SoulBondLevel {
+		for other, relation := range state.Relationships {
+			if other != name && relation.Level >= SoulBondLevel {
+				level = SoulBondLevel - 0.5
+				warning = fmt.Sprintf("LV5 羁绊只能有一个对象，已与「%s」缔结", other)
+				break
+			}
+		}
+	}
+	info.Level = level
+	if info.Tag == "" {
+		info.Tag = current.Tag
+	}
+	if info.Desc == "" {
+		info.Desc = current.Desc
+	}
+	state.Relationships[name] = info
+
+	if !known || current.Level != level {
+		if state.RelationshipHistory == nil {
+			state.RelationshipHistory = make(map[string][]model.RelationChange)
+		}
+		history := append(state.RelationshipHistory[name], model.RelationChange{
+			Turn: state.Turn + 1,
+			Week: model.WeekOf(state.Status),
+			From: current.Level,
+			To:   level,
+			Tag:  info.Tag,
+			Desc: info.Desc,
+		})
+		if len(history) > maxRelationHistory {
+			history = history[len(history)-maxRelationHistory:]
+		}
+		state.RelationshipHistory[name] = history
+	}
+	return warning
+}
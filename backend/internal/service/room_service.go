@@ -0,0 +1,103 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+const (
+	secretRoom          = "有求必应屋"
+	secretRoomCorridor  = "八楼走廊"
+	secretRoomMental    = 75 // 心智必须高于该值才能发现
+	maxSecretRoomForms  = 30
+	secretRoomNeedRunes = 20
+)
+
+// roomNeedPattern 玩家指令里说出的需求，如「我急需一个能练习决斗的地方」
+var roomNeedPattern = regexp.MustCompile(`(?:需要|想要|渴望|急需|需求是)(?:一个|一间|一处)?([^，。！？；,.!?;\s]+)`)
+
+// requirementRoomVisit 玩家在八楼挂毯对面寻找有求必应屋时判定门会不会出现。
+// 只能从八楼走廊(或已在屋内)进入；第一次发现还需要强烈而具体的需求并且心智 >75，发现之后在走廊提到它即可再用
+func requirementRoomVisit(state *model.GameState, input string) *model.RoomVisit {
+	node, _ := LookupLocation(state.Status.Location)
+	here := node.Name == secretRoomCorridor || node.Name == secretRoom
+	need := roomNeed(input)
+	if !containsAny(input, secretRoom, "来去屋", "八楼", "巴拿巴挂毯") && !(here && need != "") {
+		return nil
+	}
+	visit := &model.RoomVisit{Need: need}
+	for _, form := range state.SecretRoom.Forms {
+		visit.KnownForms = append(visit.KnownForms, form.Form)
+	}
+	if !here {
+		visit.Reason = fmt.Sprintf("必须先来到%s，在巴拿巴挂毯对面的墙前来回走三趟", secretRoomCorridor)
+		return visit
+	}
+	if state.SecretRoom.Discovered {
+		visit.Open = true
+		return visit
+	}
+	if node.Name != secretRoomCorridor {
+		visit.Reason = fmt.Sprintf("第一次寻找必须在%s的挂毯对面来回走三趟", secretRoomCorridor)
+		return visit
+	}
+	if need == "" || !containsAny(input, "迫切", "急需", "非常需要", "强烈", "拼命", "走三趟", "来回走") {
+		visit.Reason = "必须怀着强烈而具体的需求，在挂毯对面的墙前来回走三趟"
+		return visit
+	}
+	check := ThresholdCheck(state, secretRoom, "mental", secretRoomMental)
+	visit.Check = &check
+	if check.Outcome != OutcomeSuccess {
+		visit.Reason = fmt.Sprintf("心智 %d 未超过 %d，墙上没有出现门", state.Status.Mental, secretRoomMental)
+		return visit
+	}
+	week := model.WeekOf(state.Status)
+	state.SecretRoom.Discovered = true
+	state.SecretRoom.DiscoveredAt = &week
+	state.SecretRoom.DiscoveredTurn = state.Turn + 1
+	visit.Open, visit.Discovery = true, true
+	return visit
+}
+
+func roomNeed(input string) string {
+	match := roomNeedPattern.FindStringSubmatch(input)
+	if match == nil {
+		return ""
+	}
+	return string([]rune(match[1])[:min(len([]rune(match[1])), secretRoomNeedRunes)])
+}
+
+// guardSecretRoom 有求必应屋没有向角色显现时，撤回模型让角色走进去的移动
+func guardSecretRoom(state *model.GameState, before string, visit *model.RoomVisit) []string {
+	node, ok := LookupLocation(state.Status.Location)
+	if !ok || node.Name != secretRoom || state.Status.Location == before || (visit != nil && visit.Open) {
+		return nil
+	}
+	state.Status.Location = before
+	return []string{fmt.Sprintf("%s尚未向角色显现（需要强烈而具体的需求且心智 >%d），已撤回进入该地点的移动", secretRoom, secretRoomMental)}
+}
+
+// recordRoomForm 记下有求必应屋本回合变成的样子，同一形态再次出现时累计使用次数
+func recordRoomForm(state *model.GameState, visit *model.RoomVisit, form string) []string {
+	form = strings.TrimSpace(form)
+	if form == "" {
+		return nil
+	}
+	if visit == nil || !visit.Open {
+		return []string{fmt.Sprintf("%s本回合没有打开，忽略形态「%s」", secretRoom, form)}
+	}
+	rooms := &state.SecretRoom
+	if index := slices.IndexFunc(rooms.Forms, func(f model.RoomForm) bool { return f.Form == form }); index != -1 {
+		rooms.Forms[index].Uses++
+		return nil
+	}
+	rooms.Forms = append(rooms.Forms, model.RoomForm{Need: visit.Need, Form: form, Since: model.WeekOf(state.Status), Uses: 1})
+	if len(rooms.Forms) > maxSecretRoomForms {
+		rooms.Forms = rooms.Forms[len(rooms.Forms)-maxSecretRoomForms:]
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/config"
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+var shopCatalog = mustLoadCatalog[[]model.Shop]("商店目录", config.ShopCatalog)
+
+type ShopService struct{}
+
+// Catalog 全部商店及商品
+func (s *ShopService) Catalog() []model.Shop {
+	return shopCatalog
+}
+
+// Available 角色所在地点、当前年级能光顾的商店，只保留买得了的商品
+func (s *ShopService) Available(state model.GameState) []model.Shop {
+	year := SchoolYear(state.Status)
+	shops := []model.Shop{}
+	for _, shop := range shopCatalog {
+		if !shopNearby(shop, state.Status.Location) || year < shop.MinYear {
+			continue
+		}
+		items := []model.ShopItem{}
+		for _, item := range shop.Items {
+			if year >= item.MinYear {
+				items = append(items, item)
+			}
+		}
+		shop.Items = items
+		shops = append(shops, shop)
+	}
+	return shops
+}
+
+// shopNearby 地点写的是商店所在街区或商店本身都算在店里
+func shopNearby(shop model.Shop, location string) bool {
+	return location != "" && (strings.Contains(location, shop.Area) || strings.Contains(location, shop.Name))
+}
+
+// LookupShopItem 在商店目录中按商品名精确查找(书名号可省略)，同名商品优先取指定的商店、角色所在街区的商店
+func LookupShopItem(location, shopName, itemName string) (model.Shop, model.ShopItem, bool) {
+	best := -1
+	var foundShop model.Shop
+	var foundItem model.ShopItem
+	for _, shop := range shopCatalog {
+		for _, item := range shop.Items {
+			if strings.Trim(item.Name, "《》") != strings.Trim(itemName, "《》") {
+				continue
+			}
+			score := 0
+			if shopName != "" && strings.Contains(shop.Name, shopName) {
+				score += 2
+			}
+			if shopNearby(shop, location) {
+				score++
+			}
+			if score > best {
+				best, foundShop, foundItem = score, shop, item
+			}
+		}
+	}
+	return foundShop, foundItem, best >= 0
+}
+
+// isPurchase 填写了单价或商店的 add 事件按购买结算，其余视为获赠或拾得
+func isPurchase(event model.InventoryEvent) bool {
+	return event.Op == "add" && (len(event.Price) > 0 || event.Shop != "")
+}
+
+// applyPurchase 结算一笔购买：核对目录、年级与价格区间后按数量从钱包扣款，没写单价时按目录标价下限计。
+// 不可堆叠的物品一次只买一件；扣款失败时返回 false，物品不进入背包
+func applyPurchase(state *model.GameState, event *model.InventoryEvent) (bool, string) {
+	var price model.Money
+	if len(event.Price) > 0 {
+		parsed, err := ParseAmount(event.Price)
+		if err != nil {
+			return false, fmt.Sprintf("购买 %s 失败：%s", event.Item, err)
+		}
+		price = max(parsed, -parsed)
+	}
+	shop, item, listed := LookupShopItem(state.Status.Location, event.Shop, event.Item)
+	if !listed && len(event.Price) == 0 {
+		return false, fmt.Sprintf("%s 不在商店目录中，购买时必须填写单价", event.Item)
+	}
+	var warning string
+	if listed {
+		year := SchoolYear(state.Status)
+		switch {
+		case year < max(shop.MinYear, item.MinYear):
+			return false, fmt.Sprintf("%s 要 %d 年级才能购买，当前 %d 年级", item.Name, max(shop.MinYear, item.MinYear), year)
+		case !shopNearby(shop, state.Status.Location):
+			return false, fmt.Sprintf("%s 只在%s的%s出售，当前位置：%s", item.Name, shop.Area, shop.Name, state.Status.Location)
+		case len(event.Price) == 0:
+			price = item.PriceMin
+		case price < item.PriceMin || price > item.PriceMax:
+			clamped := min(max(price, item.PriceMin), item.PriceMax)
+			warning = fmt.Sprintf("%s 的标价为 %s~%s，已按 %s 结算", item.Name, item.PriceMin, item.PriceMax, clamped)
+			price = clamped
+		}
+		if event.Category == "" {
+			event.Category = item.Category
+		}
+	}
+	held, owned := state.Inventory[event.Item]
+	quantity := max(event.Quantity, 1)
+	if !itemStackable(held, owned, *event) {
+		if owned {
+			return false, fmt.Sprintf("已经有一件%s，不可堆叠的物品不能重复购买", event.Item)
+		}
+		quantity = 1
+	}
+	event.Quantity = quantity
+	reason := "购买" + event.Item
+	if quantity > 1 {
+		reason = fmt.Sprintf("购买%s×%d", event.Item, quantity)
+	}
+	if err := Charge(state, -price*model.Money(quantity), reason); err != nil {
+		return false, err.Error()
+	}
+	return true, warning
+}
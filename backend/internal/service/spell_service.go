@@ -0,0 +1,152 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/config"
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+const (
+	MaxSpellLevel            = 4
+	UnknownSpellTier         = 4  // 图鉴中查不到的咒语按专家级处理，防止战力崩坏
+	UnknownSpellMinKnowledge = 85 // 专家级的学识门槛
+)
+
+// 与咒语共用熟练度的通用技能，不受咒语位阶门槛约束
+var generalSkills = []string{
+	"变形术", "魔咒学", "魔药学", "草药学", "黑魔法防御术", "天文学", "魔法史", "飞行",
+	"占卜学", "保护神奇动物", "算术占卜", "古代如尼文", "麻瓜研究", "幻影显形", "魁地奇",
+}
+
+var (
+	spellCatalog = mustLoadCatalog[[]model.SpellEntry]("咒语图鉴", config.SpellCatalog)
+	// 模型常写成 "除你武器(Expelliarmus)"
+	spellNamePattern = regexp.MustCompile(`^\s*([^(（]+?)\s*(?:[(（]\s*([^)）]+?)\s*[)）])?\s*$`)
+)
+
+type SpellService struct{}
+
+// Catalog 完整的咒语图鉴
+func (s *SpellService) Catalog() []model.SpellEntry {
+	return spellCatalog
+}
+
+// Learnable 角色当前学识足以学会、但尚未掌握的咒语
+func (s *SpellService) Learnable(state model.GameState) []model.SpellEntry {
+	learnable := []model.SpellEntry{}
+	for _, entry := range spellCatalog {
+		if _, known := state.Spells[entry.Name]; known {
+			continue
+		}
+		if state.Status.Knowledge >= entry.MinKnowledge {
+			learnable = append(learnable, entry)
+		}
+	}
+	return learnable
+}
+
+// LookupSpell 按中文名、别名或英文咒语查找图鉴条目
+func LookupSpell(name string) (model.SpellEntry, bool) {
+	candidates := []string{name}
+	if match := spellNamePattern.FindStringSubmatch(name); match != nil {
+		candidates = append(candidates, match[1], match[2])
+	}
+	for _, candidate := range candidates {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "" {
+			continue
+		}
+		for _, entry := range spellCatalog {
+			if candidate == entry.Name || strings.EqualFold(candidate, entry.Incantation) {
+				return entry, true
+			}
+			for _, alias := range entry.Aliases {
+				if candidate == alias {
+					return entry, true
+				}
+			}
+		}
+	}
+	return model.SpellEntry{}, false
+}
+
+// spellDC 练习咒语的难度，位阶越高越难
+func spellDC(name string) float64 {
+	tier := UnknownSpellTier
+	if entry, ok := LookupSpell(name); ok {
+		tier = entry.Tier
+	}
+	return 25 + float64(tier)*10
+}
+
+// applySpellUpdate 按图鉴校验模型给出的习得或熟练度变化，返回被驳回或修正的原因
+func applySpellUpdate(state *model.GameState, name string, info model.SpellInfo) string {
+	entry, found := LookupSpell(name)
+	if found {
+		name = entry.Name
+	} else if slices.Contains(generalSkills, name) {
+		entry = model.SpellEntry{Name: name}
+	} else {
+		entry = model.SpellEntry{Name: name, Tier: UnknownSpellTier, MinKnowledge: UnknownSpellMinKnowledge}
+	}
+	knowledge := state.Status.Knowledge
+	current, known := state.Spells[name]
+
+	if !known && knowledge < entry.MinKnowledge {
+		return fmt.Sprintf("学识 %d 未达到 T%d 咒语「%s」的门槛 %d，无法习得", knowledge, entry.Tier, name, entry.MinKnowledge)
+	}
+	if known && info.Level > current.Level && knowledge < entry.MinKnowledge {
+		return fmt.Sprintf("学识不足，越级练习「%s」会遭反噬，熟练度不能提升", name)
+	}
+
+	warning := ""
+	level := min(max(info.Level, 0), MaxSpellLevel)
+	// 没有无声咒时只拦住升到 LV4，旧存档里已经是 LV4 的咒语不降级
+	if ceiling := max(MaxSpellLevel-0.5, current.Level); level > ceiling && !hasSilentCasting(state) {
+		level = ceiling
+		warning = fmt.Sprintf("「%s」需先学会无声施法才能达到 LV4", name)
+	}
+	if known && level > current.Level+1 {
+		level = current.Level + 1
+		warning = fmt.Sprintf("「%s」熟练度单回合最多提升 1 级", name)
+	} else if !known && level > 1 {
+		level = 1
+		warning = fmt.Sprintf("「%s」刚刚习得，熟练度最高 LV1", name)
+	}
+	info.Level = level
+	if info.Desc == "" {
+		info.Desc = current.Desc
+	}
+	state.Spells[name] = info
+	return warning
+}
+
+func hasSilentCasting(state *model.GameState) bool {
+	info, ok := state.Spells["无声咒"]
+	return ok && info.Level >= 1
+}
+
+// migrateSpellNames 旧存档里 "除你武器(Expelliarmus)" 这类键并入图鉴名，重复的条目保留熟练度更高的一条
+func migrateSpellNames(state *model.GameState) {
+	for _, key := range slices.Sorted(maps.Keys(state.Spells)) {
+		entry, ok := LookupSpell(key)
+		if !ok || entry.Name == key {
+			continue
+		}
+		legacy := state.Spells[key]
+		delete(state.Spells, key)
+		current, known := state.Spells[entry.Name]
+		if known && current.Level >= legacy.Level {
+			continue
+		}
+		if legacy.Desc == "" {
+			legacy.Desc = current.Desc
+		}
+		state.Spells[entry.Name] = legacy
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+var (
+	stateUpdatePattern   = regexp.MustCompile(`(?s)<state_update>(.*?)</state_update>`)
+	trailingCommaPattern = regexp.MustCompile(`,(\s*[}\]])`)
+)
+
+// 由后端结算、不允许模型直接改写的 status 字段
+var protectedStatusKeys = []string{"ap", "max_ap", "overdraft", "purse"}
+
+// ParseStateUpdate 提取回复中的 <state_update>，没有该标签时返回 nil
+func ParseStateUpdate(reply string) (*model.StateUpdate, error) {
+	match := stateUpdatePattern.FindStringSubmatch(reply)
+	if match == nil {
+		return nil, nil
+	}
+	raw := strings.TrimSpace(match[1])
+	raw = strings.TrimPrefix(raw, "```json")
+	raw = strings.TrimSuffix(strings.TrimPrefix(raw, "```"), "```")
+
+	var update model.StateUpdate
+	if err := json.Unmarshal([]byte(raw), &update); err == nil {
+		return &update, nil
+	}
+	if err := json.Unmarshal([]byte(repairJSON(raw)), &update); err != nil {
+		return nil, errors.New("状态更新 JSON 解析失败")
+	}
+	return &update, nil
+}
+
+// repairJSON 去掉模型模仿示例写出的 // 注释和多余的尾逗号
+func repairJSON(raw string) string {
+	var out strings.Builder
+	inString, escaped := false, false
+	for i := 0; i < len(raw); i++ {
+		ch := raw[i]
+		if inString {
+			out.WriteByte(ch)
+			if escaped {
+				escaped = false
+			} else if ch == '\\' {
+				escaped = true
+			} else if ch == '"' {
+				inString = false
+			}
+			continue
+		}
+		switch {
+		case ch == '"':
+			inString = true
+			out.WriteByte(ch)
+		case ch == '/' && i+1 < len(raw) && raw[i+1] == '/':
+			for i < len(raw) && raw[i] != '\n' {
+				i++
+			}
+			out.WriteByte('\n')
+		default:
+			out.WriteByte(ch)
+		}
+	}
+	return trailingCommaPattern.ReplaceAllString(out.String(), "$1")
+}
+
+// mergeStatus 把增量 status 合并进当前状态，受保护字段会被忽略
+func mergeStatus(status *model.CharacterStatus, patch map[string]json.RawMessage) error {
+	if len(patch) == 0 {
+		return nil
+	}
+	current, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	original := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(current, &original); err != nil {
+		return err
+	}
+	merged := maps.Clone(original)
+	for key, value := range patch {
+		merged[key] = value
+	}
+	for _, key := range protectedStatusKeys {
+		merged[key] = original[key]
+	}
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	var next model.CharacterStatus
+	if err := json.Unmarshal(data, &next); err != nil {
+		return errors.New("状态字段类型错误")
+	}
+	*status = next
+	return nil
+}
+
+const (
+	calendarFirstYear = 1991 // 故事从 1991 年入学开始
+	calendarLastYear  = 2020
+	maxWeekJump       = 12 // 在校期间单回合最多推进的周数，足够跨过整个暑假
+	// 毕业后一回合可以是数月或数年，最多推进五年
+	maxGraduateWeekJump = 5 * 12 * model.WeeksPerMonth
+)
+
+// weekJumpLimit 单回合最多推进的周数，毕业后放宽
+func weekJumpLimit(state *model.GameState) int {
+	if graduated(state) {
+		return maxGraduateWeekJump
+	}
+	return maxWeekJump
+}
+
+// validWeek 日历字段是否落在游戏的时间范围内
+func validWeek(week model.GameWeek) bool {
+	return week.Year >= calendarFirstYear && week.Year <= calendarLastYear &&
+		week.Month >= 1 && week.Month <= 12 && week.Week >= 1 && week.Week <= model.WeeksPerMonth
+}
+
+// guardCalendar 撤回模型写出的非法日期、时间倒流或一次跳过太多周的推进
+func guardCalendar(status *model.CharacterStatus, before model.GameWeek, limit int) []string {
+	after := model.WeekOf(*status)
+	if after == before {
+		return nil
+	}
+	var warning string
+	switch {
+	case !validWeek(after):
+		warning = fmt.Sprintf("日期「%s」不合法", after)
+	case !validWeek(before):
+		// 原状态的日期本身就不合法时，接受模型给出的合法日期，但不补算中间的周
+		return nil
+	case after.Index() < before.Index():
+		warning = fmt.Sprintf("时间不能从 %s 倒退到 %s", before, after)
+	case after.Index()-before.Index() > limit:
+		warning = fmt.Sprintf("单回合最多推进 %d 周，不能从 %s 直接跳到 %s", limit, before, after)
+	default:
+		return nil
+	}
+	status.CurrentYear, status.CurrentMonth, status.CurrentWeek = before.Year, before.Month, before.Week
+	return []string{warning + "，已撤回日期变化"}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/config"
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+// 原著事件在当前世界线中的状态
+const (
+	CanonIntact    = "canon"
+	CanonAltered   = "altered"
+	CanonPrevented = "prevented"
+)
+
+const (
+	canonLookahead  = 4 // 提前告诉 GM 接下来几周的原著事件
+	canonRewindable = 4 // 原著事件过去超过这么多周就不能再改写
+)
+
+var canonTimeline = mustLoadCatalog[[]model.CanonEvent]("原著时间线", config.TimelineCatalog)
+
+type TimelineService struct{}
+
+// Drift 世界线偏离原著的程度
+type Drift struct {
+	Events    []model.CanonStatus `json:"events"`
+	Altered   int                 `json:"altered"`
+	Prevented int                 `json:"prevented"`
+	Percent   float64             `json:"percent"` // 被改写或阻止的原著事件占全部事件的百分比
+}
+
+// Timeline 原著时间线上的全部关键事件
+func (s *TimelineService) Timeline() []model.CanonEvent {
+	return canonTimeline
+}
+
+// Drift 逐个列出原著事件在这条世界线中的状态
+func (s *TimelineService) Drift(state model.GameState) Drift {
+	drift := Drift{Events: []model.CanonStatus{}}
+	for _, event := range canonTimeline {
+		status := canonStatus(&state, event)
+		switch status.Status {
+		case CanonAltered:
+			drift.Altered++
+		case CanonPrevented:
+			drift.Prevented++
+		}
+		drift.Events = append(drift.Events, status)
+	}
+	if len(canonTimeline) > 0 {
+		drift.Percent = math.Round(float64(drift.Altered+drift.Prevented)/float64(len(canonTimeline))*1000) / 10
+	}
+	return drift
+}
+
+func canonStatus(state *model.GameState, event model.CanonEvent) model.CanonStatus {
+	status := model.CanonStatus{ID: event.ID, Name: event.Name, Week: event.Week, Location: event.Location, Status: CanonIntact}
+	if divergence := divergenceOf(state, event.ID); divergence != nil {
+		status.Status, status.How = CanonAltered, divergence.How
+		if divergence.Prevented {
+			status.Status = CanonPrevented
+		}
+	}
+	return status
+}
+
+func divergenceOf(state *model.GameState, id string) *model.Divergence {
+	index := slices.IndexFunc(state.Divergences, func(d model.Divergence) bool { return d.Event == id })
+	if index == -1 {
+		return nil
+	}
+	return &state.Divergences[index]
+}
+
+// LookupCanonEvent 按 ID 或事件名精确查找原著事件，不做模糊匹配，以免偏离记到别的事件上
+func LookupCanonEvent(text string) (model.CanonEvent, bool) {
+	text = strings.TrimSpace(text)
+	for _, event := range canonTimeline {
+		if text != "" && (event.ID == text || event.Name == text) {
+			return event, true
+		}
+	}
+	return model.CanonEvent{}, false
+}
+
+// upcomingCanon 本周及接下来几周的原著事件，连同被改写的状态一起交给 GM
+func upcomingCanon(state *model.GameState) []model.CanonStatus {
+	now := model.WeekOf(state.Status).Index()
+	upcoming := []model.CanonStatus{}
+	for _, event := range canonTimeline {
+		if index := event.Week.Index(); index >= now && index <= now+canonLookahead {
+			upcoming = append(upcoming, canonStatus(state, event))
+		}
+	}
+	return upcoming
+}
+
+// applyDivergences 记录角色对原著事件的改写，同步写入世界线日志；早已按原著发生的事件不能再改写
+func applyDivergences(state *model.GameState, changes []model.DivergenceChange) []string {
+	var warnings []string
+	week := model.WeekOf(state.Status)
+	for _, change := range changes {
+		if change.Event == "" {
+			continue
+		}
+		event, ok := LookupCanonEvent(change.Event)
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("原著时间线中没有「%s」", change.Event))
+			continue
+		}
+		divergence := divergenceOf(state, event.ID)
+		if divergence == nil && week.Index()-event.Week.Index() > canonRewindable {
+			warnings = append(warnings, fmt.Sprintf("「%s」已于 %s 按原著发生，无法改写", event.Name, event.Week))
+			continue
+		}
+		if divergence == nil {
+			state.Divergences = append(state.Divergences, model.Divergence{Event: event.ID, Name: event.Name})
+			divergence = &state.Divergences[len(state.Divergences)-1]
+		}
+		divergence.How = change.How
+		divergence.Prevented = divergence.Prevented || change.Prevented
+		divergence.Turn = state.Turn + 1
+		divergence.Week = week
+		verb := "改写"
+		if divergence.Prevented {
+			verb = "阻止"
+		}
+		state.WorldLog = append(state.WorldLog, fmt.Sprintf("【%s原著】%s：%s", verb, event.Name, change.How))
+	}
+	return warnings
+}
+
+// spareNPC 原著中死于某一事件的人物，在这件事被阻止后不再按原著去世
+func spareNPC(state *model.GameState, entry model.NPCEntry) model.NPCEntry {
+	if entry.Died == nil {
+		return entry
+	}
+	for _, event := range canonTimeline {
+		if slices.Contains(event.Deaths, entry.Name) {
+			if divergence := divergenceOf(state, event.ID); divergence != nil && divergence.Prevented {
+				entry.Died = nil
+			}
+		}
+	}
+	return entry
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+const (
+	ElectiveYear     = 3 // 三年级起上选修课，二年级末选课
+	MinElectives     = 2
+	NEWTMinOWLGrades = "OE" // 进入 N.E.W.T. 课程需要 O.W.L. 拿到 O 或 E
+)
+
+var (
+	weekdays = []string{"周一", "周二", "周三", "周四", "周五"}
+	periods  = []string{"上午", "下午", "晚上"}
+)
+
+type TimetableService struct{}
+
+// EnrolmentOptions 角色当前可以选择的选修课与 N.E.W.T. 课程，不在选课阶段时为空
+type EnrolmentOptions struct {
+	Electives    []model.SubjectEntry `json:"electives"`
+	NEWTSubjects []model.SubjectEntry `json:"newt_subjects"`
+}
+
+// Timetable 本周课表，假期、复活节与考试月没有常规课程
+func (s *TimetableService) Timetable(state model.GameState) []model.TimetableEntry {
+	entries := []model.TimetableEntry{}
+	if !isClassWeek(model.WeekOf(state.Status)) {
+		return entries
+	}
+	level := ""
+	if SchoolYear(state.Status) > OWLYear {
+		level = "N.E.W.T."
+	}
+	for _, subject := range EnrolledSubjects(state) {
+		for _, slot := range subject.Schedule {
+			entries = append(entries, model.TimetableEntry{
+				Subject: subject.Name,
+				Slot:    slot,
+				Houses:  classHouses(subject, state.Profile.House),
+				Level:   level,
+			})
+		}
+	}
+	slices.SortStableFunc(entries, func(a, b model.TimetableEntry) int {
+		return slotOrder(a.Slot) - slotOrder(b.Slot)
+	})
+	return entries
+}
+
+// Options 二至五年级可以选修课，五年级考完 O.W.L. 后可以选 N.E.W.T. 课程
+func (s *TimetableService) Options(state model.GameState) EnrolmentOptions {
+	year := SchoolYear(state.Status)
+	options := EnrolmentOptions{Electives: []model.SubjectEntry{}, NEWTSubjects: []model.SubjectEntry{}}
+	if year >= ElectiveYear-1 && year <= OWLYear {
+		for _, subject := range subjectCatalog {
+			if !subject.Core && subject.FromYear == ElectiveYear {
+				options.Electives = append(options.Electives, subject)
+			}
+		}
+	}
+	if owl := owlReportCard(state); year > OWLYear || (year == OWLYear && owl != nil) {
+		for _, subject := range subjectCatalog {
+			if subject.Exam && subject.ToYear > OWLYear && (subject.Core || slices.Contains(state.Enrolment.Electives, subject.Name)) && owlQualifies(owl, subject.Name) {
+				options.NEWTSubjects = append(options.NEWTSubjects, subject)
+			}
+		}
+	}
+	return options
+}
+
+// Enrol 校验并保存选课，nil 的列表表示不修改
+func (s *TimetableService) Enrol(state *model.GameState, change model.Enrolment) error {
+	options := s.Options(*state)
+	if change.Electives != nil {
+		if len(options.Electives) == 0 {
+			return errors.New("选修课在二年级末选定，五年级之后不能再更换")
+		}
+		electives, err := pickSubjects(change.Electives, options.Electives)
+		if err != nil {
+			return err
+		}
+		if len(electives) < MinElectives {
+			return fmt.Errorf("至少需要选修 %d 门课程", MinElectives)
+		}
+		if clash := scheduleClash(electives); clash != "" && !hasTimeTurner(state.Inventory) {
+			return fmt.Errorf("%s 上课时间冲突，除非拥有时间转换器", clash)
+		}
+		state.Enrolment.Electives = electives
+	}
+	if change.NEWTSubjects != nil {
+		if len(options.NEWTSubjects) == 0 {
+			return errors.New("通过 O.W.L. 考试后才能选择 N.E.W.T. 课程")
+		}
+		subjects, err := pickSubjects(change.NEWTSubjects, options.NEWTSubjects)
+		if err != nil {
+			return err
+		}
+		if len(subjects) == 0 {
+			return errors.New("至少需要选择一门 N.E.W.T. 课程")
+		}
+		state.Enrolment.NEWTSubjects = subjects
+	}
+	return nil
+}
+
+// EnrolledSubjects 角色本学年修读的课程：五年级前为必修课加选修课，六年级起为 N.E.W.T. 课程，
+// 没有选 N.E.W.T. 课程的存档沿用必修课
+func EnrolledSubjects(state model.GameState) []model.SubjectEntry {
+	return enrolledSubjectsIn(state, SchoolYear(state.Status))
+}
+
+func enrolledSubjectsIn(state model.GameState, year int) []model.SubjectEntry {
+	chosen := state.Enrolment.Electives
+	if year > OWLYear && len(state.Enrolment.NEWTSubjects) > 0 {
+		chosen = state.Enrolment.NEWTSubjects
+	}
+	subjects := []model.SubjectEntry{}
+	for _, subject := range subjectCatalog {
+		if year < subject.FromYear || year > subject.ToYear {
+			continue
+		}
+		core := subject.Core && (year <= OWLYear || len(state.Enrolment.NEWTSubjects) == 0)
+		if core || slices.Contains(chosen, subject.Name) {
+			subjects = append(subjects, subject)
+		}
+	}
+	return subjects
+}
+
+// isClassWeek 有常规课程的周
+func isClassWeek(week model.GameWeek) bool {
+	switch {
+	case !isTermWeek(week):
+		return false
+	case week.Month == 4 && week.Week <= 2: // 复活节假期
+		return false
+	case week.Month == ExamMonth:
+		return false
+	}
+	return true
+}
+
+// classHouses 与玩家学院一起上这门课的学院
+func classHouses(subject model.SubjectEntry, house string) []string {
+	if subject.Mixed {
+		return slices.Clone(Houses)
+	}
+	for _, pair := range subject.Pairs {
+		if slices.Contains(pair, house) {
+			return pair
+		}
+	}
+	if house == "" {
+		return []string{}
+	}
+	return []string{house}
+}
+
+func slotOrder(slot model.ClassSlot) int {
+	return slices.Index(weekdays, slot.Day)*len(periods) + slices.Index(periods, slot.Period)
+}
+
+// pickSubjects 按可选列表校验并去重
+func pickSubjects(names []string, allowed []model.SubjectEntry) ([]string, error) {
+	picked := []string{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if slices.Contains(picked, name) {
+			continue
+		}
+		if !slices.ContainsFunc(allowed, func(subject model.SubjectEntry) bool { return subject.Name == name }) {
+			return nil, fmt.Errorf("当前不能选择课程「%s」", name)
+		}
+		picked = append(picked, name)
+	}
+	return picked, nil
+}
+
+// scheduleClash 返回上课时间冲突的两门课，没有冲突时为空
+func scheduleClash(names []string) string {
+	seen := map[model.ClassSlot]string{}
+	for _, subject := range subjectCatalog {
+		if !slices.Contains(names, subject.Name) {
+			continue
+		}
+		for _, slot := range subject.Schedule {
+			if other, ok := seen[slot]; ok {
+				return other + "与" + subject.Name
+			}
+			seen[slot] = subject.Name
+		}
+	}
+	return ""
+}
+
+func hasTimeTurner(inventory model.InventoryMap) bool {
+	for name := range inventory {
+		if strings.Contains(name, "时间转换器") {
+			return true
+		}
+	}
+	return false
+}
+
+// owlReportCard 五年级的 O.W.L. 成绩单
+func owlReportCard(state model.GameState) *model.ReportCard {
+	for i := len(state.ReportCards) - 1; i >= 0; i-- {
+		if state.ReportCards[i].Year == OWLYear {
+			return &state.ReportCards[i]
+		}
+	}
+	return nil
+}
+
+// owlQualifies 没有 O.W.L. 成绩的存档不做限制
+func owlQualifies(owl *model.ReportCard, subject string) bool {
+	if owl == nil {
+		return true
+	}
+	for _, grade := range owl.Grades {
+		if grade.Subject == subject {
+			return strings.Contains(NEWTMinOWLGrades, grade.Grade)
+		}
+	}
+	return false
+}
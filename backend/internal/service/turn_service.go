@@ -0,0 +1,210 @@
+package service
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+type TurnService struct {
+	timetable   TimetableService
+	assignments AssignmentService
+	potions     PotionService
+	npcs        NPCService
+	factions    FactionService
+}
+
+// Prepare 调用模型前的规则判定，玩家行动不合法时直接驳回本回合
+func (s *TurnService) Prepare(state *model.GameState, input string) (*model.TurnContext, error) {
+	syncWallet(&state.Status)
+	migrateSpellNames(state)
+	turnCtx := &model.TurnContext{APAvailable: state.Status.AP}
+	cost, chosen := ResolveActionCost(input, state.PendingOptions)
+	turnCtx.ChosenOptions = chosen
+	if usesAP(state.Status) {
+		if err := CheckAPBudget(state.Status, cost); err != nil {
+			return nil, err
+		}
+		turnCtx.APCost = cost
+		turnCtx.Overdraft = max(0, cost-state.Status.AP)
+	}
+	recordAttendance(state, input)
+	skipDetention(state, input)
+	turnCtx.Checks = RollTurnChecks(state, input)
+	if opponent, ok := duelOpponent(input, SchoolYear(state.Status)); ok {
+		turnCtx.Duel = simulateDuel(state, opponent, input)
+	}
+	if recipe, ok := brewRecipe(input); ok {
+		turnCtx.Brew = s.potions.Brew(state, recipe)
+	}
+	turnCtx.HouseStandings = state.HousePoints.Totals
+	turnCtx.HouseCup = houseCupThisWeek(state)
+	turnCtx.Purse = state.Status.Purse.String()
+	turnCtx.ReportCard = reportCardThisTerm(state)
+	turnCtx.Timetable = s.timetable.Timetable(*state)
+	turnCtx.Homework = s.assignments.Outstanding(*state)
+	turnCtx.Match = matchThisWeek(state)
+	turnCtx.Cast = s.npcs.Cast(*state)
+	turnCtx.KnownNames = KnownNames(*state)
+	turnCtx.Night = isNight(input)
+	turnCtx.Detentions = outstandingDetentions(state)
+	turnCtx.Room = requirementRoomVisit(state, input)
+	turnCtx.YearEnd = yearEndThisWeek(state)
+	turnCtx.Booklist = booklistThisSummer(state)
+	turnCtx.Career = careerSummary(state)
+	turnCtx.Factions = s.factions.Standings(*state)
+	turnCtx.Gated = gatedContent(state, input)
+	turnCtx.Canon = upcomingCanon(state)
+	return turnCtx, nil
+}
+
+// Resolve 结算模型回复：扣除 AP、应用状态更新，并处理日历推进带来的变化
+func (s *TurnService) Resolve(state *model.GameState, turnCtx *model.TurnContext, reply string) *model.TurnResult {
+	result := &model.TurnResult{Warnings: []string{}}
+	spendAP(&state.Status, turnCtx.APCost)
+	applyDuel(state, turnCtx.Duel)
+	reputationFromActions(state, turnCtx.ChosenOptions)
+	before := model.WeekOf(state.Status)
+	location := state.Status.Location
+
+	update, err := ParseStateUpdate(reply)
+	if err != nil {
+		result.Warnings = append(result.Warnings, err.Error())
+	}
+	if update != nil {
+		// 决斗的 HP/MP 已由规则结算，不接受模型改写
+		if turnCtx.Duel != nil {
+			delete(update.Status, "hp")
+			delete(update.Status, "mp")
+		}
+		if turnCtx.Brew != nil {
+			update.InventoryEvents = dropBrewEvents(update.InventoryEvents, turnCtx.Brew)
+		}
+		result.Warnings = append(result.Warnings, s.applyUpdate(state, update)...)
+		result.Warnings = append(result.Warnings, recordRoomForm(state, turnCtx.Room, update.RoomForm)...)
+	}
+	result.Warnings = append(result.Warnings, guardSecretRoom(state, location, turnCtx.Room)...)
+	result.Warnings = append(result.Warnings, recordMovement(state, location, turnCtx.Night)...)
+	if unknown := unknownNamesIn(state, reply); len(unknown) > 0 {
+		result.Warnings = append(result.Warnings, "正文直呼了主角尚不认识的人物："+strings.Join(unknown, "、"))
+	}
+
+	// 只补算合法日期之间、有限步数内的周
+	if after := model.WeekOf(state.Status); validWeek(before) && after.Index()-before.Index() <= weekJumpLimit(state) {
+		for index := before.Index() + 1; index <= after.Index(); index++ {
+			s.advanceWeek(state, model.WeekFromIndex(index))
+		}
+	}
+	syncWeakness(state)
+
+	result.Options = ParseActionOptions(reply, state.Status.GameMode == "event")
+	state.PendingOptions = result.Options
+
+	state.Turn++
+	state.TurnContext = nil
+	result.GameState = *state
+	return result
+}
+
+func (s *TurnService) applyUpdate(state *model.GameState, update *model.StateUpdate) []string {
+	var warnings []string
+	goldBefore := state.Status.Gold
+	weekBefore := model.WeekOf(state.Status)
+	if err := mergeStatus(&state.Status, update.Status); err != nil {
+		warnings = append(warnings, err.Error())
+	}
+	warnings = append(warnings, guardCalendar(&state.Status, weekBefore, weekJumpLimit(state))...)
+	// 模型直接改写的 gold 先撤回，统一交给 applyTransactions 记账；
+	// 本回合有购买时以购买扣款为准，避免同一笔消费扣两次
+	goldDelta := state.Status.Gold - goldBefore
+	state.Status.Gold = goldBefore
+	if slices.ContainsFunc(update.InventoryEvents, isPurchase) {
+		goldDelta = 0
+	}
+	state.Status.Location = NormalizeLocation(state.Status.Location)
+	// 分院时更新学院
+	if update.House != "" {
+		state.Profile.House = update.House
+	}
+	if state.Inventory == nil {
+		state.Inventory = make(model.InventoryMap)
+	}
+	var used []string
+	for _, event := range update.InventoryEvents {
+		if event.Item != "" {
+			warnings = append(warnings, applyInventoryEvent(state, event)...)
+		}
+		if event.Op == "use" {
+			used = append(used, event.Item)
+		}
+	}
+	if state.Spells == nil {
+		state.Spells = make(model.SpellMap)
+	}
+	for _, name := range slices.Sorted(maps.Keys(update.Spells)) {
+		if warning := applySpellUpdate(state, name, update.Spells[name]); warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+	if state.Relationships == nil {
+		state.Relationships = make(model.RelationshipMap)
+	}
+	for _, name := range slices.Sorted(maps.Keys(update.Relationships)) {
+		// 不该登场的原著人物只提示，不阻止羁绊变化
+		if warning := npcAbsence(state, name); warning != "" {
+			warnings = append(warnings, warning)
+		}
+		if warning := applyRelationshipUpdate(state, name, update.Relationships[name]); warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+	learnNames(state, slices.Concat(update.KnownNames, slices.Sorted(maps.Keys(update.Relationships))))
+	if update.WorldLogAdd != "" {
+		state.WorldLog = append(state.WorldLog, update.WorldLogAdd)
+	}
+	warnings = append(warnings, applyHousePoints(state, update.HousePoints)...)
+	warnings = append(warnings, applyTransactions(state, goldDelta, update.Transactions)...)
+	warnings = append(warnings, applyAssignments(state, update.Assignments)...)
+	warnings = append(warnings, applyQuidditchChange(state, update.Quidditch)...)
+	warnings = append(warnings, applyViolations(state, update.Violations)...)
+	warnings = append(warnings, applyEffectChanges(state, update.Effects)...)
+	warnings = append(warnings, applyCareerChange(state, update.Career)...)
+	warnings = append(warnings, applyGoalChanges(state, update.Goals)...)
+	warnings = append(warnings, applyReputation(state, update.Reputation)...)
+	warnings = append(warnings, applyDivergences(state, update.Divergences)...)
+	cureEffects(state, used)
+	if update.Enrolment != nil {
+		if err := s.timetable.Enrol(state, *update.Enrolment); err != nil {
+			warnings = append(warnings, err.Error())
+		}
+	}
+	return warnings
+}
+
+// advanceWeek 日历每向前推进一周执行一次
+func (s *TurnService) advanceWeek(state *model.GameState, week model.GameWeek) {
+	rolloverAP(&state.Status)
+	tickEffects(state)
+	settleOverdueAssignments(state, week)
+	settleDetentions(state, week)
+	payCareer(state, week)
+	// 毕业生不再为学院赚分，也不再参加学院比赛
+	if isTermWeek(week) && !graduated(state) {
+		simulateHousePoints(state, week)
+		playMatchesThisWeek(state, week)
+	}
+	if week.Month == ExamMonth && week.Week == ExamWeek {
+		gradeExams(state, week)
+	}
+	if week.Month == LeavingFeastMonth && week.Week == LeavingFeastWeek {
+		if !graduated(state) {
+			awardHouseCup(state, week)
+		}
+		closeSchoolYear(state, week)
+	}
+	if week.Month == TermStartMonth && week.Week == TermStartWeek {
+		openSchoolYear(state, week)
+	}
+}
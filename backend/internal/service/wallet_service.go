@@ -0,0 +1,77 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+const maxTransactions = 200
+
+// ParseAmount 解析 state_update 中的金额，纯数字按加隆计，字符串支持 "2G 5S 3K" 等写法
+func ParseAmount(raw json.RawMessage) (model.Money, error) {
+	var galleons float64
+	if err := json.Unmarshal(raw, &galleons); err == nil {
+		// 小数按纳特四舍五入，与 model.ParseMoney 一致
+		return model.Money(math.Round(galleons * model.KnutsPerGalleon)), nil
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return 0, errors.New("金额格式错误")
+	}
+	return model.ParseMoney(text)
+}
+
+// syncWallet 旧存档只有整数 gold，首次结算时折算进钱包
+func syncWallet(status *model.CharacterStatus) {
+	if status.Purse == 0 && status.Gold > 0 {
+		status.Purse = model.Galleons(status.Gold)
+	}
+	status.Gold = status.Purse.WholeGalleons()
+}
+
+// Charge 记一笔收支，支出超过余额时拒绝交易
+func Charge(state *model.GameState, amount model.Money, reason string) error {
+	status := &state.Status
+	if amount < 0 && status.Purse+amount < 0 {
+		return fmt.Errorf("余额不足：%s 需要 %s，钱包里只有 %s", reason, -amount, status.Purse)
+	}
+	status.Purse += amount
+	status.Gold = status.Purse.WholeGalleons()
+	state.Transactions = append(state.Transactions, model.Transaction{
+		Turn:    state.Turn + 1,
+		Week:    model.WeekOf(*status),
+		Amount:  amount,
+		Balance: status.Purse,
+		Reason:  reason,
+	})
+	if len(state.Transactions) > maxTransactions {
+		state.Transactions = state.Transactions[len(state.Transactions)-maxTransactions:]
+	}
+	return nil
+}
+
+// applyTransactions 记录 state_update 中的收支；没有流水时把模型直接改写 gold 的差额 delta 记为一笔收支
+func applyTransactions(state *model.GameState, delta int, changes []model.TransactionChange) []string {
+	var warnings []string
+	// 同时给出流水时以流水为准，避免同一笔消费扣两次
+	if delta != 0 && len(changes) == 0 {
+		if err := Charge(state, model.Galleons(delta), "未注明用途的金币变化"); err != nil {
+			warnings = append(warnings, err.Error())
+		}
+	}
+	for _, change := range changes {
+		amount, err := ParseAmount(change.Amount)
+		if err != nil {
+			warnings = append(warnings, err.Error())
+			continue
+		}
+		if err := Charge(state, amount, change.Reason); err != nil {
+			warnings = append(warnings, err.Error())
+		}
+	}
+	return warnings
+}
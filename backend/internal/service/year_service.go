@@ -0,0 +1,157 @@
+package service
+
+import (
+	"slices"
+
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/config"
+	"github.com/MaiXiangCatt/HogwartsSimulator/backend/internal/model"
+)
+
+const (
+	TermStartMonth = 9 // 9 月第 1 周：乘霍格沃茨特快返校
+	TermStartWeek  = 1
+
+	mpGrowthMin   = 8 // 每年自然成长 +8~10
+	mpGrowthRange = 3
+	expressStop   = "霍格沃茨特快"
+)
+
+var booklistCatalog = mustLoadCatalog[[]model.BookEntry]("书单", config.BooklistCatalog)
+
+type YearService struct{}
+
+// Booklist 暑假期间返回下一学年的书单，学期中返回本学年的书单
+func (s *YearService) Booklist(state model.GameState) model.Booklist {
+	week := model.WeekOf(state.Status)
+	if start := upcomingSchoolYear(week); start.Month != 0 {
+		week = start
+	}
+	return booklistFor(state, week)
+}
+
+// closeSchoolYear 学年终宴当周结算：魔力上限随年龄成长，归档这一年，并寄出下一学年的书单；
+// 七年级毕业之后不再结算
+func closeSchoolYear(state *model.GameState, week model.GameWeek) {
+	label := schoolYearLabel(week)
+	if slices.ContainsFunc(state.Yearbook, func(record model.YearRecord) bool { return record.SchoolYear == label || record.Graduated }) {
+		return
+	}
+	year := schoolYearAt(week)
+	status := &state.Status
+	record := model.YearRecord{
+		SchoolYear:  label,
+		Year:        year,
+		Week:        week,
+		MaxMPBefore: status.MaxMP,
+		Attendance:  attendanceRate(state, week),
+		Graduated:   year == NEWTYear,
+	}
+
+	growth := mpGrowthMin + rollDie(state, mpGrowthRange) - 1
+	if limit := min(mpYearCap[year+1], schoolMaxMP); status.MaxMP < limit {
+		status.MaxMP = min(status.MaxMP+growth, limit)
+	}
+	status.MP = status.MaxMP
+	record.MaxMP = status.MaxMP
+	record.Knowledge, record.Mental = status.Knowledge, status.Mental
+	record.Athletics, record.Charm = status.Athletics, status.Charm
+
+	if cups := state.HousePoints.Cups; len(cups) > 0 && cups[len(cups)-1].SchoolYear == label {
+		record.HouseCup = cups[len(cups)-1].Winner
+	}
+	if card := lastReportCard(state); card != nil && card.SchoolYear == label {
+		record.Exam = card.Exam
+		for _, grade := range card.Grades {
+			if grade.Passed {
+				record.Passed++
+			} else {
+				record.Failed++
+			}
+		}
+	}
+	for _, violation := range state.Violations {
+		if schoolYearLabel(violation.Week) == label {
+			record.Violations++
+		}
+	}
+	state.Yearbook = append(state.Yearbook, record)
+
+	state.Booklist = nil
+	if record.Graduated {
+		// 毕业即离开学院魁地奇队
+		state.Quidditch.Position = ""
+	} else {
+		booklist := booklistFor(*state, model.GameWeek{Year: week.Year, Month: TermStartMonth, Week: TermStartWeek})
+		state.Booklist = &booklist
+	}
+}
+
+// openSchoolYear 开学周：收到书单的学生登上霍格沃茨特快返校
+func openSchoolYear(state *model.GameState, week model.GameWeek) {
+	if state.Booklist == nil || state.Booklist.SchoolYear != schoolYearLabel(week) {
+		return
+	}
+	state.Status.Location = NormalizeLocation(expressStop)
+}
+
+// booklistFor 某一学年开学时要买的课本，按当年修读的课程筛选
+func booklistFor(state model.GameState, week model.GameWeek) model.Booklist {
+	year := schoolYearAt(week)
+	start := schoolYearStart(week)
+	subjects := []string{}
+	for _, subject := range enrolledSubjectsIn(state, year) {
+		subjects = append(subjects, subject.Name)
+	}
+	booklist := model.Booklist{SchoolYear: schoolYearLabel(week), Year: year, Books: []model.BookEntry{}}
+	for _, book := range booklistCatalog {
+		switch {
+		case year < book.FromYear || year > book.ToYear:
+		case book.From != 0 && (start < book.From || start > book.To):
+		case book.Subject != "" && !slices.Contains(subjects, book.Subject):
+		default:
+			booklist.Books = append(booklist.Books, book)
+		}
+	}
+	return booklist
+}
+
+// upcomingSchoolYear 终宴之后到开学前的暑假里返回下一学年的开学周，其余时间返回零值
+func upcomingSchoolYear(week model.GameWeek) model.GameWeek {
+	if week.Month < LeavingFeastMonth || week.Month >= TermStartMonth || (week.Month == LeavingFeastMonth && week.Week < LeavingFeastWeek) {
+		return model.GameWeek{}
+	}
+	return model.GameWeek{Year: week.Year, Month: TermStartMonth, Week: TermStartWeek}
+}
+
+// schoolYearAt 某一周所在的年级
+func schoolYearAt(week model.GameWeek) int {
+	return SchoolYear(model.CharacterStatus{CurrentYear: week.Year, CurrentMonth: week.Month})
+}
+
+// yearEndThisWeek 终宴当周返回刚刚归档的学年总结，供 GM 描写
+func yearEndThisWeek(state *model.GameState) *model.YearRecord {
+	week := model.WeekOf(state.Status)
+	if week.Month != LeavingFeastMonth || week.Week != LeavingFeastWeek || len(state.Yearbook) == 0 {
+		return nil
+	}
+	record := state.Yearbook[len(state.Yearbook)-1]
+	if record.SchoolYear != schoolYearLabel(week) {
+		return nil
+	}
+	return &record
+}
+
+// booklistThisSummer 暑假至开学周返回新学年的书单
+func booklistThisSummer(state *model.GameState) *model.Booklist {
+	week := model.WeekOf(state.Status)
+	label := schoolYearLabel(week)
+	if start := upcomingSchoolYear(week); start.Month != 0 {
+		label = schoolYearLabel(start)
+	} else if week.Month != TermStartMonth || week.Week != TermStartWeek {
+		return nil
+	}
+	if state.Booklist == nil || state.Booklist.SchoolYear != label {
+		return nil
+	}
+	return state.Booklist
+}
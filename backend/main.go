@@ -20,6 +20,32 @@ func main() {
 	{
 		api.POST("/chat", controller.ChatHandler)
 		api.POST("/ai/summarize", controller.SummarizeHandler)
+
+		api.GET("/spells", controller.GetSpellCatalog)
+		api.POST("/spells/learnable", controller.GetLearnableSpells)
+		api.POST("/relationships/timeline", controller.GetRelationshipTimeline)
+		api.GET("/shops", controller.GetShopCatalog)
+		api.POST("/shops/available", controller.GetAvailableShops)
+		api.POST("/timetable", controller.GetTimetable)
+		api.POST("/enrolment/options", controller.GetEnrolmentOptions)
+		api.POST("/enrolment", controller.Enrol)
+		api.POST("/assignments", controller.GetOutstandingAssignments)
+		api.POST("/quidditch/season", controller.GetQuidditchSeason)
+		api.POST("/duel", controller.ResolveDuel)
+		api.GET("/potions", controller.GetPotionRecipes)
+		api.POST("/potions/available", controller.GetAvailableRecipes)
+		api.GET("/npcs", controller.GetNPCRegistry)
+		api.POST("/npcs/cast", controller.GetCast)
+		api.GET("/locations", controller.GetLocationGraph)
+		api.POST("/locations/check", controller.CheckLocation)
+		api.POST("/discipline", controller.GetDiscipline)
+		api.POST("/yearbook", controller.GetYearbook)
+		api.GET("/careers", controller.GetCareers)
+		api.POST("/careers/eligibility", controller.GetCareerEligibility)
+		api.GET("/factions", controller.GetFactions)
+		api.POST("/factions/standings", controller.GetStandings)
+		api.GET("/timeline", controller.GetTimeline)
+		api.POST("/timeline/drift", controller.GetTimelineDrift)
 	}
 	r.Run(":8080")
 }
@@ -418,7 +418,14 @@ const ChatSidebar = ({ characterInfo }: ChatSidebarProps) => {
                           key={name}
                           className="bg-background/50 flex items-center justify-between gap-4 rounded-md border p-3"
                         >
-                          <span className="shrink-0 font-medium">{name}</span>
+                          <span className="shrink-0 font-medium">
+                            {name}
+                            {(info.quantity ?? 1) > 1 && (
+                              <span className="text-muted-foreground ml-1 text-xs">
+                                ×{info.quantity}
+                              </span>
+                            )}
+                          </span>
                           <span className="text-muted-foreground line-clamp-2 text-right text-xs">
                             {info.desc}
                           </span>
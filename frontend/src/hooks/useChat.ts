@@ -2,8 +2,13 @@ import { useRef, useState, useEffect } from 'react'
 import { db } from '@/lib/db'
 import { useLiveQuery } from 'dexie-react-hooks'
 import { toast } from 'sonner'
-import { parseAndUpdateState } from '@/lib/utils'
-import type { ChatResponseData } from '@/types/chat'
+import {
+  parseAndUpdateState,
+  applyServerState,
+  safeJSONParse,
+  type ServerTurnResult,
+} from '@/lib/utils'
+import type { ActionOption, ChatResponseData } from '@/types/chat'
 import { summarizeStory } from '@/services/ai'
 import { secureStorage } from '@/lib/storage'
 
@@ -134,6 +139,7 @@ export function useChat(characterId: number) {
           summary: character.summary,
           persona: character.persona || '',
           game_state: {
+            ...(character.engine_state || {}),
             profile: {
               name: character.name,
               gender: character.gender,
@@ -156,7 +162,8 @@ export function useChat(characterId: number) {
         signal: abortControllerRef.current.signal,
       })
       if (!response.ok || !response.body) {
-        throw new Error('Network response was not ok')
+        const errorBody = await response.json().catch(() => null)
+        throw new Error(errorBody?.error || 'Network response was not ok')
       }
       const reader = response.body.getReader()
       const decoder = new TextDecoder()
@@ -171,6 +178,9 @@ export function useChat(characterId: number) {
       let rawAIContent = ''
       let cleanAIContent = ''
       let reasoningContent = ''
+      let serverResult: ServerTurnResult | undefined
+      // 结算事件可能跨多个 chunk，未读完的半行留到下一次拼接
+      let pendingLine = ''
 
       while (true) {
         const { done, value } = await reader.read()
@@ -178,7 +188,8 @@ export function useChat(characterId: number) {
           break
         }
         const chunk = decoder.decode(value, { stream: true })
-        const lines = chunk.split('\n')
+        const lines = (pendingLine + chunk).split('\n')
+        pendingLine = lines.pop() || ''
         for (const line of lines) {
           if (line.startsWith('data:')) {
             const jsonStr = line.slice(5).trim()
@@ -191,7 +202,12 @@ export function useChat(characterId: number) {
             if (!message) {
               continue
             }
-            if (type === 'thought') {
+            if (type === 'state') {
+              serverResult = safeJSONParse<ServerTurnResult>(message)
+            } else if (type === 'options') {
+              const options = safeJSONParse<ActionOption[]>(message, [])
+              await db.logs.update(aiMessageId, { options })
+            } else if (type === 'thought') {
               reasoningContent += message
               await db.logs.update(aiMessageId, {
                 reasoning_content: reasoningContent,
@@ -218,7 +234,11 @@ export function useChat(characterId: number) {
       }
 
       console.log('Final Raw Content:', rawAIContent) // Debug log
-      await parseAndUpdateState(characterId, rawAIContent)
+      if (serverResult) {
+        await applyServerState(characterId, serverResult)
+      } else {
+        await parseAndUpdateState(characterId, rawAIContent)
+      }
     } catch (error: any) {
       if (error.name === 'AbortError') {
         return
@@ -6,6 +6,7 @@ import type {
   CharacterStatus,
   SpellInfo,
   RelationInfo,
+  InventoryItemInfo,
 } from '@/types/character'
 
 interface AIStateUpdatePayload {
@@ -21,6 +22,20 @@ interface AIStateUpdatePayload {
   world_log_add?: string
 }
 
+// 后端回合结算后推送的权威状态 (SSE type=state)
+export interface ServerTurnResult {
+  game_state: {
+    profile: { house: string }
+    status: CharacterStatus
+    inventory: Record<string, InventoryItemInfo>
+    spells: Record<string, SpellInfo>
+    relationships: Record<string, RelationInfo>
+    world_log: string[]
+    [key: string]: unknown
+  }
+  warnings: string[]
+}
+
 export function cn(...inputs: ClassValue[]) {
   return twMerge(clsx(inputs))
 }
@@ -130,3 +145,35 @@ export const parseAndUpdateState = async (
     console.error('解析状态更新失败:', error)
   }
 }
+
+export const applyServerState = async (
+  characterId: number,
+  result: ServerTurnResult
+) => {
+  const {
+    profile,
+    status,
+    inventory,
+    spells,
+    relationships,
+    world_log,
+    ...engineState
+  } = result.game_state
+  const updatesToApply: Partial<Character> = {
+    updated_at: Date.now(),
+    status,
+    inventory: inventory || {},
+    spells: spells || {},
+    relationships: relationships || {},
+    world_log: world_log || [],
+    engine_state: engineState,
+  }
+  if (profile?.house && profile.house !== '未分院') {
+    updatesToApply.house = profile.house
+  }
+  await db.characters.update(characterId, updatesToApply)
+
+  if (result.warnings?.length) {
+    console.warn('规则引擎修正:', result.warnings)
+  }
+}
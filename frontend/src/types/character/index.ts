@@ -20,6 +20,8 @@ export interface CharacterStatus {
   current_weekday: number
   location: string
   game_mode: string
+  overdraft?: number // 本周已透支的 AP，由后端结算
+  purse?: number // 钱包余额 (纳特)，gold 为其中的整加隆数
 }
 
 export interface SpellInfo {
@@ -35,6 +37,10 @@ export interface RelationInfo {
 
 export interface InventoryItemInfo {
   desc: string
+  quantity?: number // 数量，缺省为 1
+  category?: string // book | ingredient | pet | equipment | artifact | consumable
+  stackable?: boolean
+  effects?: Record<string, number> // 使用时对状态的影响
 }
 
 export interface Character {
@@ -60,6 +66,9 @@ export interface Character {
 
   updated_at: number // 时间戳
   last_summary_timestamp: number // 上次自动总结的时间戳
+
+  // 后端规则引擎维护的其余存档字段，原样随 game_state 往返
+  engine_state?: Record<string, unknown>
 }
 
 // 保留原有的 API 响应接口，或者根据新架构调整。
@@ -3,12 +3,21 @@ export interface ChatMessageType {
   content: string
 }
 
+// 后端从 GM 回复 Part 2 中解析出的行动选项
+export interface ActionOption {
+  category: string
+  label: string
+  ap_cost: number
+  is_event: boolean
+}
+
 export interface ChatLog {
   id?: number
   character_id: number
   role: 'user' | 'assistant' | 'system'
   content: string
   reasoning_content?: string
+  options?: ActionOption[]
   timestamp: number
 }
 